@@ -1,11 +1,17 @@
 load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")
-load("//golangci_lint/private:versions.bzl", "get_golangci_version_info", "DEFAULT_VERSION")
+load("//golangci_lint/private:toolchain.bzl", "toolchains_repo")
+load("//golangci_lint/private:toolchains.bzl", "golangci_toolchain_repositories", "golangci_toolchains_build_file")
+load("//golangci_lint/private:versions.bzl", "DEFAULT_VERSIONS", "GOLANGCI_PLATFORMS", "get_golangci_version_info")
 
 # Tag class for configuring golangci-lint version
 _config_tag = tag_class(
     attrs = {
         "version": attr.string(
-            doc = "Version of golangci-lint to use (e.g., 'v2.6.1'). If not specified, uses the default version.",
+            doc = "Version of golangci-lint to use: a tag (e.g., 'v2.6.1') or an alias tracking a release line ('latest', 'v2', 'v2.6'). If not specified, uses the default version.",
+        ),
+        "config": attr.label(
+            doc = "golangci-lint config file of this repository (e.g., '//:.golangci.yml'). If set and no version is given, its schema version selects the default release of the matching major line: 'version: \"2\"' selects v2, a config without a version selects v1.",
+            allow_single_file = True,
         ),
     },
 )
@@ -15,8 +21,11 @@ def _golangci_lint_extension_impl(ctx):
 
     # Collect version from tags across all modules
     requested_version = None
+    config_file = None
     for mod in ctx.modules:
         for config_tag in mod.tags.config:
+            if config_tag.config and mod.is_root:
+                config_file = config_tag.config
             if config_tag.version:
                 if requested_version and requested_version != config_tag.version:
                     fail("Multiple modules requested different golangci-lint versions: {} and {}".format(
@@ -25,8 +34,17 @@ def _golangci_lint_extension_impl(ctx):
                     ))
                 requested_version = config_tag.version
 
-    # Use requested version or default
+    # Use requested version, else the default of the config's major line, else the default
     version_to_use = requested_version if requested_version else None
+    if not version_to_use and config_file:
+        major = "v" + _config_schema_version(ctx, config_file)
+        if major not in DEFAULT_VERSIONS:
+            fail("No golangci-lint {} release available for config {}. Available major versions: {}".format(
+                major,
+                config_file,
+                ", ".join(DEFAULT_VERSIONS.keys()),
+            ))
+        version_to_use = DEFAULT_VERSIONS[major]
 
     # Get version and checksums from generated versions file
     version, checksums = get_golangci_version_info(version_to_use)
@@ -68,38 +86,91 @@ def _golangci_lint_extension_impl(ctx):
         build_file = "//golangci_lint:private/golangci_lint_binary.BUILD.bazel",
     )
 
+    # Every version and platform as a toolchain, for remote execution on a different platform
+    # than the host. Archives are only fetched once toolchain resolution selects them.
+    golangci_toolchain_repositories(str(Label("//golangci_lint/private:toolchain.bzl")))
+    toolchains_repo(
+        name = "golangci_lint_toolchains",
+        build_file_content = golangci_toolchains_build_file(
+            str(Label("//golangci_lint:toolchain_type")),
+            version,
+        ),
+    )
+
     return ctx.extension_metadata(
-        root_module_direct_deps = ["golangci_lint_binary"],
+        root_module_direct_deps = ["golangci_lint_binary", "golangci_lint_toolchains"],
         root_module_direct_dev_deps = [],
         reproducible = True,
     )
 
-def _detect_platforms(ctx):
-    """Detects the platforms of the current build."""
+def _config_schema_version(ctx, config_file):
+    """Reads the schema version of a golangci-lint config file.
 
-    os_map = {
-        "linux": "linux",
-        "mac os x": "darwin",
-        "windows": "windows",
-    }
+    golangci-lint v2 requires a top-level `version: "2"`; v1 configs have none.
 
-    arch_map = {
-        "x86_64": "amd64",
-        "amd64": "amd64",  # Some systems report as amd64 instead of x86_64
-        "aarch64": "arm64",
-        "arm64": "arm64",
-    }
+    Args:
+        ctx: The module context
+        config_file: Label of a .yml, .yaml, .toml or .json config file
 
-    os_name = ctx.os.name.lower()
-    os_arch = ctx.os.arch.lower()
+    Returns:
+        The schema version as a string, "1" if the file does not declare one.
+    """
+    content = ctx.read(ctx.path(config_file))
+
+    if config_file.name.endswith(".json"):
+        return str(json.decode(content).get("version", "1"))
 
-    if os_name not in os_map:
-        fail("Unsupported operating system: {}. Supported: {}".format(os_name, ", ".join(os_map.keys())))
+    # YAML and TOML: a top-level "version: ..." or "version = ..." line
+    for line in content.splitlines():
+        if not line.startswith("version"):
+            continue
+        rest = line.removeprefix("version").lstrip()
+        if not rest or rest[0] not in ":=":
+            continue
+        value = rest[1:].split("#")[0].strip().strip("\"'")
+        if value:
+            return value
+    return "1"
+
+def _detect_platforms(ctx):
+    """Detects the platform of the current host among the generated platforms.
 
-    if os_arch not in arch_map:
-        fail("Unsupported architecture: {}. Supported: {}".format(os_arch, ", ".join(arch_map.keys())))
+    The host OS and CPU are normalised to @platforms constraint values and matched
+    against GOLANGCI_PLATFORMS, so every published platform is supported without a
+    hand-maintained map of release names.
 
-    return os_map[os_name], arch_map[os_arch]
+    Returns:
+        The (os, arch) release names of the host platform.
+    """
+    os_name = ctx.os.name.lower()
+    if os_name.startswith("mac os"):
+        os_name = "macos"
+    elif os_name.startswith("windows"):
+        os_name = "windows"
+
+    # Names the JVM reports that differ from the @platforms//cpu value
+    cpu_map = {
+        "amd64": "x86_64",
+        "arm64": "aarch64",
+        "i386": "x86_32",
+        "i686": "x86_32",
+        "x86": "x86_32",
+        "loong64": "loongarch64",
+    }
+    os_arch = ctx.os.arch.lower()
+    cpu = cpu_map.get(os_arch, os_arch)
+
+    want = ["@platforms//os:" + os_name, "@platforms//cpu:" + cpu]
+    for platform in GOLANGCI_PLATFORMS.values():
+        if platform["constraints"] == want:
+            return platform["os"], platform["arch"]
+
+    fail("Unsupported platform: {} {} ({}). Supported: {}".format(
+        ctx.os.name,
+        ctx.os.arch,
+        ", ".join(want),
+        ", ".join(GOLANGCI_PLATFORMS.keys()),
+    ))
 
 golangci_lint = module_extension(
     implementation = _golangci_lint_extension_impl,
@@ -4,6 +4,8 @@ Runs golangci-lint on the given target.
 
 load("@rules_go//go:def.bzl", "go_context")
 
+_TOOLCHAIN_TYPE = Label("//golangci_lint:toolchain_type")
+
 def _to_rlocation_path(ctx, file):
     """Convert a File object to an rlocation path for use with runfiles.bash.
 
@@ -130,7 +132,7 @@ def _collect_runfiles(ctx, go):
         A tuple of (files_list, transitive_depset)
     """
     # Direct runfiles
-    files = [ctx.executable._golangci_lint, go.go] + ctx.files.data
+    files = [ctx.toolchains[_TOOLCHAIN_TYPE].binary, go.go] + ctx.files.data
     if ctx.file.config:
         files.append(ctx.file.config)
 
@@ -158,7 +160,7 @@ def _golangci_lint_test_impl(ctx):
     mod = _find_go_mod(ctx)
 
     # Generate rlocation paths for all dependencies
-    gcl_path = _to_rlocation_path(ctx, ctx.executable._golangci_lint)
+    gcl_path = _to_rlocation_path(ctx, ctx.toolchains[_TOOLCHAIN_TYPE].binary)
     go_tool_path = _to_rlocation_path(ctx, go.go)
     mod_path = _to_rlocation_path(ctx, mod)
     cfg_path = _to_rlocation_path(ctx, ctx.file.config) if ctx.file.config else ""
@@ -202,12 +204,6 @@ golangci_lint_test = rule(
             allow_single_file = [".yml", ".yaml", ".toml", ".json"],
             doc = "Config file for golangci-lint (v2 schema).",
         ),
-        "_golangci_lint": attr.label(
-            default = "@golangci_lint_binary//:golangci_lint",
-            executable = True,
-            cfg = "exec",
-            doc = "The golangci-lint binary to use.",
-        ),
         "_bash_runfiles": attr.label(
             default = "@bazel_tools//tools/bash/runfiles",
         ),
@@ -216,5 +212,5 @@ golangci_lint_test = rule(
         ),
     },
     test = True,
-    toolchains = ["@rules_go//go:toolchain"],
+    toolchains = ["@rules_go//go:toolchain", _TOOLCHAIN_TYPE],
 )
@@ -0,0 +1,38 @@
+"""
+Toolchain rules for prebuilt golangci-lint binaries.
+"""
+
+def _binary_toolchain_impl(ctx):
+    """Implementation of the binary_toolchain rule."""
+    return [platform_common.ToolchainInfo(
+        binary = ctx.file.binary,
+    )]
+
+binary_toolchain = rule(
+    implementation = _binary_toolchain_impl,
+    attrs = {
+        "binary": attr.label(
+            allow_single_file = True,
+            executable = True,
+            cfg = "exec",
+            mandatory = True,
+            doc = "The prebuilt executable.",
+        ),
+    },
+    doc = "Provides a prebuilt executable as ToolchainInfo.binary.",
+)
+
+def _toolchains_repo_impl(rctx):
+    """Implementation of the toolchains_repo repository rule."""
+    rctx.file("BUILD.bazel", rctx.attr.build_file_content)
+
+toolchains_repo = repository_rule(
+    implementation = _toolchains_repo_impl,
+    attrs = {
+        "build_file_content": attr.string(
+            mandatory = True,
+            doc = "BUILD file registering the toolchains.",
+        ),
+    },
+    doc = "Creates a hub repository holding the toolchain() targets of all toolchain repositories.",
+)
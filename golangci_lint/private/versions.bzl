@@ -1,10 +1,15 @@
 # Code generated by //tools/update_versions. DO NOT EDIT.
-# Generated at: 2025-11-12T16:34:46Z
 
 """Version and checksum data for golangci-lint releases."""
 
 DEFAULT_VERSION = "v2.6.1"
 
+# Default version of each major line, for repositories that cannot move to the newest major.
+DEFAULT_VERSIONS = {
+    "v1": "v1.64.8",
+    "v2": "v2.6.1",
+}
+
 GOLANGCI_VERSIONS = {
     "v2.6.1": {
         "darwin": {
@@ -47,24 +52,340 @@ GOLANGCI_VERSIONS = {
             "arm64": "eff5849a62c2b0076ab55a4b40379c8636028bccfdb8af3cc54af155e18f25dd",
         },
     },
+    "v1.64.8": {
+        "darwin": {
+            "amd64": "b52aebb8cb51e00bfd5976099083fbe2c43ef556cef9c87e58a8ae656e740444",
+            "arm64": "70543d21e5b02a94079be8aa11267a5b060865583e337fe768d39b5d3e2faf1f",
+        },
+        "freebsd": {
+            "386": "038c305e72f96e880dcc84a5742e4854358d300614c258dcc5389b4d3c24e898",
+            "amd64": "33eadbdd03352f56d882031d0b6d7ad3c379ed15c0d514e1c0b13d018e610a95",
+            "armv6": "e3088306c25fe6779447223de24721265f9e83b4e738b309383f15cbf615ad28",
+            "armv7": "a4d3b73a994b9ff1ca8f892670cb6660561fab74ecd3d511e57fac1e3dacda71",
+        },
+        "illumos": {
+            "amd64": "a8165702bdf5dd1b514ee93107a7efe147efc960763e00f32e1867de03a57fa3",
+        },
+        "linux": {
+            "386": "8c8368368887e44227f59a76a52ba7e7f849505da9c0af35559bddf92b4ccc57",
+            "amd64": "b6270687afb143d019f387c791cd2a6f1cb383be9b3124d241ca11bd3ce2e54e",
+            "arm64": "a6ab58ebcb1c48572622146cdaec2956f56871038a54ed1149f1386e287789a5",
+            "armv6": "73221a822dbe317eaa211ca03f82eb3571a39605dc407f78ab562cf5dc0b48ba",
+            "armv7": "cad656fd1328a441576ac9d667d830d382424256056a59db62083963afc94539",
+            "loong64": "caf0ee110e12be2b95e0fee072cf4c965dc13efaa931e49db4506a2f2883ec2b",
+            "mips64": "4eccdb7d6bf743a3646309621f03ddc0c13bdbc44c20cc73262ff2f1cacf3e42",
+            "mips64le": "1950df9ad10c90c1affdb1a552fca01c92ded29ff1edbad8f757a5f464a6348c",
+            "ppc64le": "9952c49473fc902601dbf65ccf6674f8336b440f74c6de96f38b0960767de6db",
+            "riscv64": "62b8ef3f71cc9513fc1e949de75cdce7d65ede1e8a7538d7413bfcade8181f89",
+            "s390x": "1a9dcb141fa5050a69a0676103de8285fce3e0458c47024d6443008f5295af67",
+        },
+        "netbsd": {
+            "386": "bd50df6be9e0f1258d9f77c3678d3f25067c95c25819455c47b728a5fdb6f475",
+            "amd64": "90bc8c3ba291391710f1b5fc84f01f8d6345a0be7ff4f422253f68a68c23df3d",
+            "arm64": "c6a67faba23fdeb40c78f91f842aecf6465b15fba4ace6b4246e810df6d62d84",
+            "armv6": "dc3b93e6d0f9647bf8f2dbe851fef5f8f8c0104dff87ae048cf3a6925cf393c6",
+            "armv7": "598d051d345681d4f1c1112568f01330ac538d8ebcb6015b0dd1053c7f5fa8c1",
+        },
+        "windows": {
+            "386": "6f975edc143a6da50de7ace4d15fdee7bccb77e5ad915196dbde279de5fdc1cb",
+            "amd64": "54c2ed3a6b4f2f5da1056fb6e83d6b73b592e06684b65a5999174fabbb251a8f",
+            "arm64": "24294bc46bc887691c7a43c66448f1df9203c0a9c15bcb114b6147aa97973505",
+            "armv6": "0472d2b147d0f3fd4bc89efd275c9f75e62af1932c8f182932df2523210caee7",
+            "armv7": "60ea7014a98790acb892ffa83a8a254bfd1b5862e8777cf333524656ea0f7b40",
+        },
+    },
+}
+
+# Go toolchain version each release was built with (recorded with --go-versions).
+GOLANGCI_GO_VERSIONS = {
+}
+
+# Where each version's checksums came from, per the updater's cache index: the release
+# source, the checksum file's URL and SHA-256, whether the file was downloaded ("network")
+# or found in the cache without a record ("cache"), when it was downloaded, and whether the
+# release signs it (signatures are not verified by the updater).
+GOLANGCI_PROVENANCE = {
+    "v2.6.1": {
+        "source": "https://github.com/golangci/golangci-lint",
+        "checksum_url": "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+        "checksum_file_sha256": "a57478bcf3a5771babfbd3bf05dcfdac1e38567f46bc54f6b4cc165ecdd8e3d3",
+        "origin": "cache",
+        "signature": "unsigned",
+    },
+    "v1.64.8": {
+        "source": "https://github.com/golangci/golangci-lint",
+        "checksum_url": "https://github.com/golangci/golangci-lint/releases/download/v1.64.8/golangci-lint-1.64.8-checksums.txt",
+        "checksum_file_sha256": "1a5543126cbe9d52dbd39ae598f4886bfb51f0bb00a0856056c666403a3cbc97",
+        "origin": "cache",
+        "signature": "unsigned",
+    },
+}
+
+# Bazel constraints of every platform in GOLANGCI_VERSIONS, keyed by "<os>_<arch>" with
+# the release names. goarm is the GOARM level of armv6/armv7 builds.
+GOLANGCI_PLATFORMS = {
+    "darwin_amd64": {
+        "os": "darwin",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:macos",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "darwin_arm64": {
+        "os": "darwin",
+        "arch": "arm64",
+        "constraints": [
+            "@platforms//os:macos",
+            "@platforms//cpu:aarch64",
+        ],
+    },
+    "freebsd_386": {
+        "os": "freebsd",
+        "arch": "386",
+        "constraints": [
+            "@platforms//os:freebsd",
+            "@platforms//cpu:x86_32",
+        ],
+    },
+    "freebsd_amd64": {
+        "os": "freebsd",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:freebsd",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "freebsd_arm64": {
+        "os": "freebsd",
+        "arch": "arm64",
+        "constraints": [
+            "@platforms//os:freebsd",
+            "@platforms//cpu:aarch64",
+        ],
+    },
+    "freebsd_armv6": {
+        "os": "freebsd",
+        "arch": "armv6",
+        "goarm": "6",
+        "constraints": [
+            "@platforms//os:freebsd",
+            "@platforms//cpu:arm",
+        ],
+    },
+    "freebsd_armv7": {
+        "os": "freebsd",
+        "arch": "armv7",
+        "goarm": "7",
+        "constraints": [
+            "@platforms//os:freebsd",
+            "@platforms//cpu:armv7",
+        ],
+    },
+    "illumos_amd64": {
+        "os": "illumos",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:illumos",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "linux_386": {
+        "os": "linux",
+        "arch": "386",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:x86_32",
+        ],
+    },
+    "linux_amd64": {
+        "os": "linux",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "linux_arm64": {
+        "os": "linux",
+        "arch": "arm64",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:aarch64",
+        ],
+    },
+    "linux_armv6": {
+        "os": "linux",
+        "arch": "armv6",
+        "goarm": "6",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:arm",
+        ],
+    },
+    "linux_armv7": {
+        "os": "linux",
+        "arch": "armv7",
+        "goarm": "7",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:armv7",
+        ],
+    },
+    "linux_loong64": {
+        "os": "linux",
+        "arch": "loong64",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:loongarch64",
+        ],
+    },
+    "linux_mips64": {
+        "os": "linux",
+        "arch": "mips64",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:mips64",
+        ],
+    },
+    "linux_ppc64le": {
+        "os": "linux",
+        "arch": "ppc64le",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:ppc64le",
+        ],
+    },
+    "linux_riscv64": {
+        "os": "linux",
+        "arch": "riscv64",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:riscv64",
+        ],
+    },
+    "linux_s390x": {
+        "os": "linux",
+        "arch": "s390x",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:s390x",
+        ],
+    },
+    "netbsd_386": {
+        "os": "netbsd",
+        "arch": "386",
+        "constraints": [
+            "@platforms//os:netbsd",
+            "@platforms//cpu:x86_32",
+        ],
+    },
+    "netbsd_amd64": {
+        "os": "netbsd",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:netbsd",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "netbsd_arm64": {
+        "os": "netbsd",
+        "arch": "arm64",
+        "constraints": [
+            "@platforms//os:netbsd",
+            "@platforms//cpu:aarch64",
+        ],
+    },
+    "netbsd_armv6": {
+        "os": "netbsd",
+        "arch": "armv6",
+        "goarm": "6",
+        "constraints": [
+            "@platforms//os:netbsd",
+            "@platforms//cpu:arm",
+        ],
+    },
+    "netbsd_armv7": {
+        "os": "netbsd",
+        "arch": "armv7",
+        "goarm": "7",
+        "constraints": [
+            "@platforms//os:netbsd",
+            "@platforms//cpu:armv7",
+        ],
+    },
+    "windows_386": {
+        "os": "windows",
+        "arch": "386",
+        "constraints": [
+            "@platforms//os:windows",
+            "@platforms//cpu:x86_32",
+        ],
+    },
+    "windows_amd64": {
+        "os": "windows",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:windows",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "windows_arm64": {
+        "os": "windows",
+        "arch": "arm64",
+        "constraints": [
+            "@platforms//os:windows",
+            "@platforms//cpu:aarch64",
+        ],
+    },
+    "windows_armv6": {
+        "os": "windows",
+        "arch": "armv6",
+        "goarm": "6",
+        "constraints": [
+            "@platforms//os:windows",
+            "@platforms//cpu:arm",
+        ],
+    },
+    "windows_armv7": {
+        "os": "windows",
+        "arch": "armv7",
+        "goarm": "7",
+        "constraints": [
+            "@platforms//os:windows",
+            "@platforms//cpu:armv7",
+        ],
+    },
+}
+
+# Moving aliases, each resolving to the newest stable release of its line.
+GOLANGCI_ALIASES = {
+    "latest": "v2.6.1",
+    "v2": "v2.6.1",
+    "v2.6": "v2.6.1",
+    "v1": "v1.64.8",
+    "v1.64": "v1.64.8",
 }
 
 def get_golangci_version_info(version = None):
     """Returns (version, checksums_map) for the requested version.
 
     Args:
-        version: Version tag (e.g., "v2.6.1"). If None, uses DEFAULT_VERSION.
+        version: Version tag (e.g., "v2.6.1") or alias ("latest", "v2",
+            "v2.6"). If None, uses DEFAULT_VERSION.
 
     Returns:
-        Tuple of (version_string, checksums_dict) where checksums_dict is
-        a nested dict: {os: {arch: sha256}}
+        Tuple of (version_string, checksums_dict) where version_string is the
+        resolved tag and checksums_dict is a nested dict: {os: {arch: sha256}}
 
     Fails:
         If the requested version is not available.
     """
     v = version if version else DEFAULT_VERSION
+    v = GOLANGCI_ALIASES.get(v, v)
     if v not in GOLANGCI_VERSIONS:
         fail("Unknown golangci-lint version: {}. Available: {}".format(
-            v, ", ".join(GOLANGCI_VERSIONS.keys())
+            v,
+            ", ".join(GOLANGCI_VERSIONS.keys()),
         ))
     return v, GOLANGCI_VERSIONS[v]
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AliasLatest names the newest stable version.
+const AliasLatest = "latest"
+
+// Alias maps a moving name such as "latest", "v2" or "v2.6" to a version tag.
+type Alias struct {
+	Name    string
+	Version string
+}
+
+// ComputeAliases resolves "latest" and one alias per major ("v2") and minor ("v2.6") line
+// to the newest stable version of that line by semver ordering. Prereleases and tags that
+// are not numeric versions get no alias, and an alias never shadows a real tag.
+// Aliases are ordered latest first, then by line, newest first, each major before its minors.
+func ComputeAliases(versions []VersionData) []Alias {
+	tags := make(map[string]bool, len(versions))
+	for _, v := range versions {
+		tags[v.Tag] = true
+	}
+
+	newest := make(map[string]string) // alias name -> tag
+	var lines []string
+	promote := func(name, tag string) {
+		current, ok := newest[name]
+		if !ok {
+			lines = append(lines, name)
+		}
+		if !ok || CompareVersions(tag, current) > 0 {
+			newest[name] = tag
+		}
+	}
+
+	for _, v := range versions {
+		major, minor, ok := versionLine(v.Tag)
+		if !ok {
+			continue
+		}
+		promote(AliasLatest, v.Tag)
+		promote(major, v.Tag)
+		promote(minor, v.Tag)
+	}
+
+	// latest first, then lines newest first; a major sorts before its minors
+	sort.SliceStable(lines, func(i, j int) bool {
+		if lines[i] == AliasLatest || lines[j] == AliasLatest {
+			return lines[i] == AliasLatest
+		}
+		majorI, _, minorAliasI := strings.Cut(lines[i], ".")
+		majorJ, _, minorAliasJ := strings.Cut(lines[j], ".")
+		if c := CompareVersions(majorI, majorJ); c != 0 {
+			return c > 0
+		}
+		if minorAliasI != minorAliasJ {
+			return !minorAliasI
+		}
+		return CompareVersions(lines[i], lines[j]) > 0
+	})
+
+	aliases := make([]Alias, 0, len(lines))
+	for _, name := range lines {
+		if tags[name] {
+			continue
+		}
+		aliases = append(aliases, Alias{Name: name, Version: newest[name]})
+	}
+	return aliases
+}
+
+// MajorDefault is the default version of one major line, e.g. "v1" -> "v1.64.8".
+type MajorDefault struct {
+	Major   string
+	Version string
+}
+
+// ComputeDefaultVersions returns the default version of each major line, oldest major first.
+// The line of defaultVersion defaults to it; every other line to its newest stable version.
+func ComputeDefaultVersions(defaultVersion string, versions []VersionData) []MajorDefault {
+	newest := make(map[string]string) // major -> tag
+	for _, v := range versions {
+		major, _, ok := versionLine(v.Tag)
+		if !ok {
+			continue
+		}
+		if current, seen := newest[major]; !seen || CompareVersions(v.Tag, current) > 0 {
+			newest[major] = v.Tag
+		}
+	}
+	if major, ok := versionMajor(defaultVersion); ok {
+		newest[major] = defaultVersion
+	}
+
+	majors := make([]string, 0, len(newest))
+	for major := range newest {
+		majors = append(majors, major)
+	}
+	sort.Slice(majors, func(i, j int) bool {
+		return CompareVersions(majors[i], majors[j]) < 0
+	})
+
+	defaults := make([]MajorDefault, 0, len(majors))
+	for _, major := range majors {
+		defaults = append(defaults, MajorDefault{Major: major, Version: newest[major]})
+	}
+	return defaults
+}
+
+// versionMajor returns the major line ("v2") of any numeric tag, including prereleases.
+func versionMajor(tag string) (string, bool) {
+	core, _ := splitVersion(tag)
+	if len(core) == 0 {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(core[0], 10, 64); err != nil {
+		return "", false
+	}
+	if strings.HasPrefix(tag, "v") {
+		return "v" + core[0], true
+	}
+	return core[0], true
+}
+
+// versionLine returns the major ("v2") and minor ("v2.6") alias names of a stable tag,
+// keeping the tag's "v" prefix if it has one.
+func versionLine(tag string) (major, minor string, ok bool) {
+	core, pre := splitVersion(tag)
+	if pre != nil || len(core) < 2 {
+		return "", "", false
+	}
+	for _, part := range core {
+		if _, err := strconv.ParseUint(part, 10, 64); err != nil {
+			return "", "", false
+		}
+	}
+
+	prefix := ""
+	if strings.HasPrefix(tag, "v") {
+		prefix = "v"
+	}
+	return prefix + core[0], prefix + core[0] + "." + core[1], true
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeAliases(t *testing.T) {
+	versions := []VersionData{
+		{Tag: "v2.7.0-rc.1"},
+		{Tag: "v2.6.1"},
+		{Tag: "v2.6.0"},
+		{Tag: "v2.5.2"},
+		{Tag: "v2.10.0"},
+		{Tag: "v1.64.8"},
+		{Tag: "v1.63.4"},
+	}
+
+	assert.Equal(t, []Alias{
+		{Name: "latest", Version: "v2.10.0"},
+		{Name: "v2", Version: "v2.10.0"},
+		{Name: "v2.10", Version: "v2.10.0"},
+		{Name: "v2.6", Version: "v2.6.1"},
+		{Name: "v2.5", Version: "v2.5.2"},
+		{Name: "v1", Version: "v1.64.8"},
+		{Name: "v1.64", Version: "v1.64.8"},
+		{Name: "v1.63", Version: "v1.63.4"},
+	}, ComputeAliases(versions), "ComputeAliases() should resolve each line by semver, skipping prereleases")
+}
+
+func TestComputeAliases_EdgeCases(t *testing.T) {
+	tests := []struct {
+		name     string
+		versions []VersionData
+		want     []Alias
+	}{
+		{
+			name:     "no versions",
+			versions: nil,
+			want:     []Alias{},
+		},
+		{
+			name:     "only prereleases",
+			versions: []VersionData{{Tag: "v3.0.0-beta.1"}},
+			want:     []Alias{},
+		},
+		{
+			name:     "tags without v prefix",
+			versions: []VersionData{{Tag: "1.2.3"}, {Tag: "1.2.10"}},
+			want:     []Alias{{Name: "latest", Version: "1.2.10"}, {Name: "1", Version: "1.2.10"}, {Name: "1.2", Version: "1.2.10"}},
+		},
+		{
+			name:     "non-numeric tags",
+			versions: []VersionData{{Tag: "nightly"}, {Tag: "v2.x.1"}},
+			want:     []Alias{},
+		},
+		{
+			name:     "alias never shadows a tag",
+			versions: []VersionData{{Tag: "v2.6.1"}, {Tag: "v2.6"}},
+			want:     []Alias{{Name: "latest", Version: "v2.6.1"}, {Name: "v2", Version: "v2.6.1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ComputeAliases(tt.versions), "ComputeAliases() returned unexpected aliases")
+		})
+	}
+}
+
+func TestGenerateOutput_AliasesAndDefaults(t *testing.T) {
+	data := &TemplateData{
+		ToolName:       "golangci-lint",
+		VarPrefix:      "GOLANGCI",
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "aaa"}}},
+			{Tag: "v1.64.8", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "bbb"}}},
+		},
+	}
+	data.DefaultVersions = ComputeDefaultVersions(data.DefaultVersion, data.Versions)
+	data.Aliases = ComputeAliases(data.Versions)
+	tempDir := t.TempDir()
+
+	t.Run("starlark", func(t *testing.T) {
+		path := filepath.Join(tempDir, "versions.bzl")
+		_, err := GenerateStarlarkFile(data, path)
+		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+
+		raw, err := os.ReadFile(path)
+		require.NoError(t, err, "Failed to read output")
+		content := string(raw)
+		assert.Contains(t, content, "GOLANGCI_ALIASES = {", "output should define the alias table")
+		assert.Contains(t, content, `"v1": "v1.64.8",`, "output should map the v1 line to its last release")
+		assert.Contains(t, content, "v = GOLANGCI_ALIASES.get(v, v)", "get_golangci_version_info() should resolve aliases")
+		assert.Contains(t, content, "DEFAULT_VERSIONS = {\n    \"v1\": \"v1.64.8\",\n    \"v2\": \"v2.6.1\",\n}",
+			"output should define the default of each major line")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		content, err := RenderJSON(data)
+		require.NoError(t, err, "RenderJSON() should succeed")
+		assert.Contains(t, string(content), `"aliases": {`, "JSON should include the alias table")
+		assert.Contains(t, string(content), `"v2.6": "v2.6.1"`, "JSON should map minor lines")
+		assert.Contains(t, string(content), `"default_versions": {`, "JSON should include the default of each major line")
+	})
+}
+
+func TestComputeDefaultVersions(t *testing.T) {
+	versions := []VersionData{
+		{Tag: "v2.7.0-rc.1"},
+		{Tag: "v2.6.1"},
+		{Tag: "v1.64.8"},
+		{Tag: "v1.63.4"},
+		{Tag: "v1.65.0-beta.1"},
+	}
+
+	assert.Equal(t, []MajorDefault{
+		{Major: "v1", Version: "v1.64.8"},
+		{Major: "v2", Version: "v2.6.1"},
+	}, ComputeDefaultVersions("v2.6.1", versions), "ComputeDefaultVersions() should pick the newest stable release of each major")
+
+	assert.Equal(t, []MajorDefault{
+		{Major: "v1", Version: "v1.64.8"},
+		{Major: "v2", Version: "v2.7.0-rc.1"},
+	}, ComputeDefaultVersions("v2.7.0-rc.1", versions), "ComputeDefaultVersions() should keep the overall default for its major")
+
+	assert.Empty(t, ComputeDefaultVersions("", nil), "ComputeDefaultVersions() should be empty without versions")
+}
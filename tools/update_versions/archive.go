@@ -0,0 +1,209 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// errNotInArchive is returned when no archive entry matches.
+var errNotInArchive = errors.New("file not found in archive")
+
+// readArchiveFile returns the contents and path of the first regular file in a .tar.gz,
+// .tgz or .zip archive for which match returns true. The format is chosen by archiveName.
+// A matching file larger than maxArchiveEntrySize is an error.
+func readArchiveFile(archiveName string, data []byte, match func(name string) bool) ([]byte, string, error) {
+	switch {
+	case strings.HasSuffix(archiveName, ".zip"):
+		return readZipFile(data, match, maxArchiveEntrySize)
+	case strings.HasSuffix(archiveName, ".tar.gz"), strings.HasSuffix(archiveName, ".tgz"):
+		return readTarGzFile(data, match, maxArchiveEntrySize)
+	default:
+		return nil, "", fmt.Errorf("unsupported archive format: %s", archiveName)
+	}
+}
+
+// readTarGzFile implements readArchiveFile for gzip-compressed tarballs.
+func readTarGzFile(data []byte, match func(name string) bool, limit int64) ([]byte, string, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, "", errNotInArchive
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read tar entry: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg || !match(hdr.Name) {
+			continue
+		}
+
+		content, err := readLimited(tr, limit)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		return content, hdr.Name, nil
+	}
+}
+
+// readZipFile implements readArchiveFile for zip archives.
+func readZipFile(data []byte, match func(name string) bool, limit int64) ([]byte, string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || !match(f.Name) {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		content, err := readLimited(rc, limit)
+		_ = rc.Close()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		return content, f.Name, nil
+	}
+
+	return nil, "", errNotInArchive
+}
+
+// baseNameIs returns a matcher for archive entries whose base name is one of names.
+func baseNameIs(names ...string) func(string) bool {
+	return func(name string) bool {
+		base := path.Base(name)
+		for _, n := range names {
+			if base == n {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// extractArchive writes the regular files of a .tar.gz, .tgz or .zip archive below dest,
+// dropping the leading stripPrefix directory like http_archive's strip_prefix. Entries
+// that would land outside dest or exceed maxArchiveEntrySize are rejected; links and other
+// special files are skipped.
+func extractArchive(archiveName string, data []byte, dest, stripPrefix string) error {
+	var files []archiveEntry
+	var err error
+	switch {
+	case strings.HasSuffix(archiveName, ".zip"):
+		files, err = zipEntries(data, maxArchiveEntrySize)
+	case strings.HasSuffix(archiveName, ".tar.gz"), strings.HasSuffix(archiveName, ".tgz"):
+		files, err = tarGzEntries(data, maxArchiveEntrySize)
+	default:
+		return fmt.Errorf("unsupported archive format: %s", archiveName)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		name := path.Clean(f.name)
+		if stripPrefix != "" && strings.HasPrefix(name, stripPrefix+"/") {
+			name = strings.TrimPrefix(name, stripPrefix+"/")
+		}
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("archive entry %q escapes the destination", f.name)
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", name, err)
+		}
+		mode := os.FileMode(0644)
+		if f.mode&0111 != 0 {
+			mode = 0755
+		}
+		if err := os.WriteFile(target, f.content, mode); err != nil {
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// archiveEntry is a regular file read from an archive.
+type archiveEntry struct {
+	name    string
+	mode    os.FileMode
+	content []byte
+}
+
+// tarGzEntries returns the regular files of a gzip-compressed tarball, failing on any
+// larger than limit.
+func tarGzEntries(data []byte, limit int64) ([]archiveEntry, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open gzip stream: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var entries []archiveEntry
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar entry: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		content, err := readLimited(tr, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", hdr.Name, err)
+		}
+		entries = append(entries, archiveEntry{name: hdr.Name, mode: hdr.FileInfo().Mode(), content: content})
+	}
+}
+
+// zipEntries returns the regular files of a zip archive, failing on any larger than limit.
+func zipEntries(data []byte, limit int64) ([]archiveEntry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	var entries []archiveEntry
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+		}
+		content, err := readLimited(rc, limit)
+		_ = rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+		}
+		entries = append(entries, archiveEntry{name: f.Name, mode: f.Mode(), content: content})
+	}
+	return entries, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+)
+
+// findChecksumAsset returns the first asset whose name matches glob.
+func findChecksumAsset(assets []Asset, glob string) (*Asset, bool) {
+	for i := range assets {
+		if ok, _ := path.Match(glob, assets[i].Name); ok {
+			return &assets[i], true
+		}
+	}
+	return nil, false
+}
+
+// archiveAssets returns the assets whose names match the tool's asset pattern.
+func archiveAssets(assets []Asset, pattern *AssetPattern) []Asset {
+	var matched []Asset
+	for _, a := range assets {
+		if _, err := pattern.ExtractPlatform(a.Name); err == nil {
+			matched = append(matched, a)
+		}
+	}
+	return matched
+}
+
+// computeChecksumFile downloads each archive and returns a checksum file in
+// sha256sum format ("<hash>  <name>" per line, sorted by name), so releases
+// without a published checksum file are cached and parsed like any other.
+func computeChecksumFile(ctx context.Context, source ReleaseSource, archives []Asset) ([]byte, error) {
+	sorted := append([]Asset(nil), archives...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var b strings.Builder
+	for _, a := range sorted {
+		log.Printf("  Computing SHA-256 of %s...", a.Name)
+		data, err := source.DownloadAsset(ctx, a.URL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download %s: %w", a.Name, err)
+		}
+		if a.Size > 0 && int64(len(data)) != a.Size {
+			return nil, fmt.Errorf("downloaded %s is %d bytes, release metadata says %d", a.Name, len(data), a.Size)
+		}
+
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(&b, "%s  %s\n", hex.EncodeToString(sum[:]), a.Name)
+	}
+
+	return []byte(b.String()), nil
+}
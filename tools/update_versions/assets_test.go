@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindChecksumAsset(t *testing.T) {
+	assets := []Asset{
+		{Name: "golangci-lint-2.6.1-linux-amd64.tar.gz"},
+		{Name: "golangci-lint-2.6.1-checksums.txt", URL: "https://example.com/sums"},
+		{Name: "golangci-lint-2.6.1-checksums.txt.sig"},
+	}
+
+	asset, ok := findChecksumAsset(assets, "*-checksums.txt")
+	require.True(t, ok, "findChecksumAsset() should find matching asset")
+	assert.Equal(t, "https://example.com/sums", asset.URL, "findChecksumAsset() should return the matching asset")
+
+	_, ok = findChecksumAsset(assets, "SHA256SUMS")
+	assert.False(t, ok, "findChecksumAsset() should report no match")
+}
+
+func TestArchiveAssets(t *testing.T) {
+	assets := []Asset{
+		{Name: "golangci-lint-2.6.1-linux-amd64.tar.gz"},
+		{Name: "golangci-lint-2.6.1-linux-amd64.deb"},
+		{Name: "golangci-lint-2.6.1-windows-amd64.zip"},
+		{Name: "README.md"},
+	}
+
+	archives := archiveAssets(assets, defaultAssetPattern)
+
+	require.Len(t, archives, 2, "archiveAssets() should keep only assets matching the pattern")
+	assert.Equal(t, "golangci-lint-2.6.1-linux-amd64.tar.gz", archives[0].Name, "archiveAssets() should preserve order")
+	assert.Equal(t, "golangci-lint-2.6.1-windows-amd64.zip", archives[1].Name, "archiveAssets() should preserve order")
+}
+
+func TestComputeChecksumFile(t *testing.T) {
+	linux := []byte("linux archive")
+	darwin := []byte("darwin archive")
+
+	mock := NewMockGitHubClient()
+	mock.AddAsset("https://example.com/linux", linux)
+	mock.AddAsset("https://example.com/darwin", darwin)
+
+	t.Run("sorted sha256sum output", func(t *testing.T) {
+		data, err := computeChecksumFile(context.Background(), mock, []Asset{
+			{Name: "tool-1.0.0-linux-amd64.tar.gz", URL: "https://example.com/linux", Size: int64(len(linux))},
+			{Name: "tool-1.0.0-darwin-arm64.tar.gz", URL: "https://example.com/darwin"},
+		})
+		require.NoError(t, err, "computeChecksumFile() should succeed")
+
+		expected := fmt.Sprintf("%s  tool-1.0.0-darwin-arm64.tar.gz\n%s  tool-1.0.0-linux-amd64.tar.gz\n", sha256Hex(darwin), sha256Hex(linux))
+		assert.Equal(t, expected, string(data), "computeChecksumFile() should emit sorted sha256sum lines")
+	})
+
+	t.Run("size mismatch", func(t *testing.T) {
+		_, err := computeChecksumFile(context.Background(), mock, []Asset{
+			{Name: "tool-1.0.0-linux-amd64.tar.gz", URL: "https://example.com/linux", Size: 1},
+		})
+		assert.Error(t, err, "computeChecksumFile() should reject size mismatch")
+	})
+
+	t.Run("download failure", func(t *testing.T) {
+		_, err := computeChecksumFile(context.Background(), mock, []Asset{
+			{Name: "tool-1.0.0-linux-amd64.tar.gz", URL: "https://example.com/missing"},
+		})
+		assert.Error(t, err, "computeChecksumFile() should propagate download errors")
+	})
+}
+
+func TestRunner_Run_ChecksumAssetFoundByGlob(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tool := DefaultTool()
+	tool.OutputFile = "versions.bzl"
+	tool.ChecksumGlob = "*checksums*.txt"
+
+	// Upstream renamed the checksum file; the conventional URL no longer exists
+	mock := NewMockGitHubClient()
+	mock.AddReleaseWithAssets("v2.6.1",
+		Asset{Name: "golangci-lint-2.6.1-linux-amd64.tar.gz", URL: "https://example.com/archive"},
+		Asset{Name: "golangci-lint_2.6.1_checksums_sha256.txt", URL: "https://example.com/renamed-sums"},
+	)
+	mock.AddAsset("https://example.com/renamed-sums",
+		[]byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"))
+
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{tool},
+	}
+
+	err := NewRunner(config, mock).Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed")
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "versions.bzl"))
+	require.NoError(t, err, "Failed to read output file")
+	assert.Contains(t, string(content), "aaa1111111111111111111111111111111111111111111111111111111111111", "Runner.Run() should use the checksum asset found by glob")
+}
+
+func TestRunner_Run_ComputesChecksumsWithoutChecksumFile(t *testing.T) {
+	tempDir := t.TempDir()
+	cacheDir := filepath.Join(tempDir, "cache")
+	archive := []byte("golangci-lint linux archive")
+
+	mock := NewMockGitHubClient()
+	mock.AddReleaseWithAssets("v2.6.1",
+		Asset{Name: "golangci-lint-2.6.1-linux-amd64.tar.gz", URL: "https://example.com/archive", Size: int64(len(archive))},
+		Asset{Name: "golangci-lint-2.6.1-linux-amd64.deb", URL: "https://example.com/deb"},
+	)
+	mock.AddAsset("https://example.com/archive", archive)
+
+	config := Config{
+		Count:         1,
+		CacheDir:      cacheDir,
+		OutputFile:    filepath.Join(tempDir, "versions.bzl"),
+		WorkspaceRoot: tempDir,
+	}
+
+	err := NewRunner(config, mock).Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed")
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "versions.bzl"))
+	require.NoError(t, err, "Failed to read output file")
+	assert.Contains(t, string(content), sha256Hex(archive), "Runner.Run() should emit the computed checksum")
+
+	cached, err := os.ReadFile(filepath.Join(cacheDir, "v2.6.1.txt"))
+	require.NoError(t, err, "Runner.Run() should cache the computed checksum file")
+	assert.Equal(t, sha256Hex(archive)+"  golangci-lint-2.6.1-linux-amd64.tar.gz\n", string(cached), "cached file should be in sha256sum format")
+}
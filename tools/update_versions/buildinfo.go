@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// buildInfoPlatform is the platform whose archive is inspected for build info.
+var buildInfoPlatform = Platform{OS: "linux", Arch: "amd64"}
+
+// ReadGoVersion returns the Go toolchain version (e.g. "1.25.3") that the named binary
+// inside a release archive was built with.
+func ReadGoVersion(archiveName string, archive []byte, binary string) (string, error) {
+	content, name, err := readArchiveFile(archiveName, archive, baseNameIs(binary, binary+".exe"))
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", binary, err)
+	}
+
+	info, err := buildinfo.Read(bytes.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf("failed to read build info from %s: %w", name, err)
+	}
+
+	return normalizeGoVersion(info.GoVersion), nil
+}
+
+// normalizeGoVersion strips the "go" prefix and any experiment suffix,
+// e.g. "go1.25.3 X:boringcrypto" -> "1.25.3".
+func normalizeGoVersion(v string) string {
+	v, _, _ = strings.Cut(v, " ")
+	return strings.TrimPrefix(v, "go")
+}
+
+// loadGoVersion returns the Go version a release's linux/amd64 binary was built with,
+// reading it from goVersionFile if cached, otherwise downloading and verifying the archive.
+func (r *Runner) loadGoVersion(ctx context.Context, tool Tool, source ReleaseSource, release Release, checksums map[Platform]string, goVersionFile string) (string, error) {
+	if data, err := os.ReadFile(goVersionFile); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	name, archive, err := downloadVerifiedArchive(ctx, tool, source, release, checksums)
+	if err != nil {
+		return "", err
+	}
+
+	goVersion, err := ReadGoVersion(name, archive, tool.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := writeFileAtomic(goVersionFile, []byte(goVersion+"\n")); err != nil {
+		log.Printf("  Warning: failed to cache Go version: %v", err)
+		// Continue anyway - we have the version
+	}
+
+	return goVersion, nil
+}
+
+// downloadVerifiedArchive downloads a release's linux/amd64 archive and verifies it against
+// the release's checksums, returning its name and contents.
+func downloadVerifiedArchive(ctx context.Context, tool Tool, source ReleaseSource, release Release, checksums map[Platform]string) (string, []byte, error) {
+	want, ok := checksums[buildInfoPlatform]
+	if !ok {
+		return "", nil, fmt.Errorf("no %s-%s checksum to verify the archive against", buildInfoPlatform.OS, buildInfoPlatform.Arch)
+	}
+
+	name, url := archiveLocation(tool, source, release, buildInfoPlatform)
+	archive, err := source.DownloadAsset(ctx, url)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return "", nil, fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return name, archive, nil
+}
+
+// archiveLocation returns the name and download URL of a release's archive for a platform,
+// preferring the release's reported assets over a name rendered from the asset pattern.
+func archiveLocation(tool Tool, source ReleaseSource, release Release, platform Platform) (name, url string) {
+	for _, a := range archiveAssets(release.Assets, tool.Pattern()) {
+		if p, err := tool.Pattern().ExtractPlatform(a.Name); err == nil && *p == platform {
+			return a.Name, a.URL
+		}
+	}
+
+	name = expandPlaceholders(tool.AssetPattern, map[string]string{
+		"name":    tool.Name,
+		"version": strings.TrimPrefix(release.TagName, "v"),
+		"os":      platform.OS,
+		"arch":    platform.Arch,
+		"ext":     archiveExt(platform.OS),
+	})
+
+	return name, source.AssetURL(tool.Repo, release.TagName, name)
+}
+
+// archiveExt returns the conventional archive extension for an OS: zip on windows, tar.gz elsewhere.
+func archiveExt(goos string) string {
+	if goos == "windows" {
+		return "zip"
+	}
+	return "tar.gz"
+}
+
+// assetURLTemplate returns the download URL of the tool's archives with {tag}, {version},
+// {os}, {arch} and {ext} left as placeholders for RenderURL.
+func assetURLTemplate(tool Tool, source ReleaseSource) string {
+	name := expandPlaceholders(tool.AssetPattern, map[string]string{"name": tool.Name})
+	url := source.AssetURL(tool.Repo, "{tag}", name)
+	// Sources may path-escape the tag and name; restore the placeholder braces.
+	return strings.NewReplacer("%7B", "{", "%7D", "}", "%7b", "{", "%7d", "}").Replace(url)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeTarGz builds a .tar.gz archive containing the given files.
+func makeTarGz(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	require.NoError(t, err, "Failed to create gzip writer")
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}), "Failed to write tar header")
+		_, err := tw.Write(content)
+		require.NoError(t, err, "Failed to write tar entry")
+	}
+	require.NoError(t, tw.Close(), "Failed to close tar writer")
+	require.NoError(t, gz.Close(), "Failed to close gzip writer")
+
+	return buf.Bytes()
+}
+
+// makeZip builds a .zip archive containing the given files.
+func makeZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err, "Failed to create zip entry")
+		_, err = w.Write(content)
+		require.NoError(t, err, "Failed to write zip entry")
+	}
+	require.NoError(t, zw.Close(), "Failed to close zip writer")
+
+	return buf.Bytes()
+}
+
+// testBinary returns the running test binary, which carries Go build info.
+func testBinary(t *testing.T) []byte {
+	t.Helper()
+
+	path, err := os.Executable()
+	require.NoError(t, err, "Failed to locate test binary")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "Failed to read test binary")
+
+	return data
+}
+
+func TestReadGoVersion(t *testing.T) {
+	binary := testBinary(t)
+	want := normalizeGoVersion(runtime.Version())
+
+	t.Run("tar.gz", func(t *testing.T) {
+		archive := makeTarGz(t, map[string][]byte{
+			"golangci-lint-2.6.1-linux-amd64/LICENSE":       []byte("license"),
+			"golangci-lint-2.6.1-linux-amd64/golangci-lint": binary,
+		})
+
+		got, err := ReadGoVersion("golangci-lint-2.6.1-linux-amd64.tar.gz", archive, "golangci-lint")
+		require.NoError(t, err, "ReadGoVersion() should succeed")
+		assert.Equal(t, want, got, "ReadGoVersion() should return the toolchain version")
+	})
+
+	t.Run("zip with exe", func(t *testing.T) {
+		archive := makeZip(t, map[string][]byte{
+			"golangci-lint-2.6.1-windows-amd64/golangci-lint.exe": binary,
+		})
+
+		got, err := ReadGoVersion("golangci-lint-2.6.1-windows-amd64.zip", archive, "golangci-lint")
+		require.NoError(t, err, "ReadGoVersion() should succeed")
+		assert.Equal(t, want, got, "ReadGoVersion() should return the toolchain version")
+	})
+
+	t.Run("binary missing", func(t *testing.T) {
+		archive := makeTarGz(t, map[string][]byte{"dir/README.md": []byte("readme")})
+
+		_, err := ReadGoVersion("tool.tar.gz", archive, "golangci-lint")
+		assert.ErrorIs(t, err, errNotInArchive, "ReadGoVersion() should report missing binary")
+	})
+
+	t.Run("not a Go binary", func(t *testing.T) {
+		archive := makeTarGz(t, map[string][]byte{"dir/golangci-lint": []byte("#!/bin/sh\n")})
+
+		_, err := ReadGoVersion("tool.tar.gz", archive, "golangci-lint")
+		assert.Error(t, err, "ReadGoVersion() should reject binaries without build info")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := ReadGoVersion("tool.rpm", []byte("rpm"), "golangci-lint")
+		assert.Error(t, err, "ReadGoVersion() should reject unknown archive formats")
+	})
+}
+
+func TestNormalizeGoVersion(t *testing.T) {
+	assert.Equal(t, "1.25.3", normalizeGoVersion("go1.25.3"), "normalizeGoVersion() should strip go prefix")
+	assert.Equal(t, "1.24.0", normalizeGoVersion("go1.24.0 X:boringcrypto"), "normalizeGoVersion() should strip experiments")
+	assert.Equal(t, "1.26rc1", normalizeGoVersion("go1.26rc1"), "normalizeGoVersion() should keep prereleases")
+}
+
+func TestRunner_Run_RecordsGoVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	cacheDir := filepath.Join(tempDir, "cache")
+	outputFile := filepath.Join(tempDir, "versions.bzl")
+
+	archive := makeTarGz(t, map[string][]byte{"golangci-lint-2.6.1-linux-amd64/golangci-lint": testBinary(t)})
+	want := normalizeGoVersion(runtime.Version())
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte(sha256Hex(archive)+"  golangci-lint-2.6.1-linux-amd64.tar.gz\n"),
+	)
+	mock.AddAsset("https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz", archive)
+
+	config := Config{
+		Count:            1,
+		CacheDir:         cacheDir,
+		OutputFile:       outputFile,
+		WorkspaceRoot:    tempDir,
+		RecordGoVersions: true,
+	}
+
+	err := NewRunner(config, mock).Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output file")
+	assert.Contains(t, string(content), `"v2.6.1": "`+want+`",`, "Runner.Run() should emit the Go version")
+
+	cached, err := os.ReadFile(filepath.Join(cacheDir, "v2.6.1.goversion"))
+	require.NoError(t, err, "Runner.Run() should cache the Go version")
+	assert.Equal(t, want, strings.TrimSpace(string(cached)), "cached Go version should match")
+
+	// Second run must use the cache instead of downloading the archive again
+	mock.DownloadError = assert.AnError
+	err = NewRunner(config, mock).Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed from cache")
+}
+
+func TestRunner_Run_GoVersionChecksumMismatch(t *testing.T) {
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "versions.bzl")
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"),
+	)
+	mock.AddAsset("https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz", []byte("tampered"))
+
+	config := Config{
+		Count:            1,
+		CacheDir:         filepath.Join(tempDir, "cache"),
+		OutputFile:       outputFile,
+		WorkspaceRoot:    tempDir,
+		RecordGoVersions: true,
+	}
+
+	// The Go version step is optional: a bad archive is reported but not fatal
+	err := NewRunner(config, mock).Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed without the Go version")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output file")
+	assert.Contains(t, string(content), "GOLANGCI_GO_VERSIONS = {\n}", "Runner.Run() should not record an unverified Go version")
+}
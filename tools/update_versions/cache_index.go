@@ -0,0 +1,171 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// CacheIndexFile is the name of the index kept in each tool's cache directory.
+const CacheIndexFile = "index.json"
+
+// lockFileName is the file locked in the cache directory while a run uses it.
+const lockFileName = ".lock"
+
+// How a checksum file entered the cache: downloaded by the updater, or found there without
+// a record (seeded by hand or cached before the index existed). The origin is kept in the
+// index, so later runs reading the file from the cache report the same provenance.
+const (
+	OriginCache   = "cache"
+	OriginNetwork = "network"
+)
+
+// Signature statuses of a checksum file. The updater does not verify signatures itself;
+// it records whether the release publishes one so auditors know what to check.
+const (
+	// SignatureUnknown means the source does not list release assets.
+	SignatureUnknown = "unknown"
+	// SignatureNone means the release publishes no signature for the checksum file.
+	SignatureNone = "unsigned"
+	// SignatureNotVerified means a signature is published but was not verified.
+	SignatureNotVerified = "not-verified"
+)
+
+// signatureExtensions are the suffixes of signature assets published next to a checksum file.
+var signatureExtensions = []string{".sig", ".asc", ".sigstore.json", ".bundle"}
+
+// CacheEntry records the origin of one cached checksum file.
+type CacheEntry struct {
+	// URL is where the file was downloaded from; empty when checksums were computed
+	// by downloading the archives.
+	URL string `json:"url,omitempty"`
+	// SHA256 is the hex digest of the cached file.
+	SHA256 string `json:"sha256"`
+	// FetchedAt is the RFC 3339 download time; empty for files cached before the index existed.
+	FetchedAt string `json:"fetched_at,omitempty"`
+	// Origin is OriginNetwork or OriginCache.
+	Origin string `json:"origin"`
+	// Signature is one of the Signature* statuses.
+	Signature string `json:"signature"`
+}
+
+// CacheIndex maps the file names in a cache directory to their entries.
+type CacheIndex struct {
+	Entries map[string]CacheEntry `json:"entries"`
+
+	path string
+}
+
+// LoadCacheIndex reads the index of a cache directory, or returns an empty one if there is none.
+func LoadCacheIndex(dir string) (*CacheIndex, error) {
+	index := &CacheIndex{Entries: make(map[string]CacheEntry), path: filepath.Join(dir, CacheIndexFile)}
+
+	data, err := os.ReadFile(index.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to parse cache index %s: %w", index.path, err)
+	}
+	if index.Entries == nil {
+		index.Entries = make(map[string]CacheEntry)
+	}
+	return index, nil
+}
+
+// Save writes the index back to its cache directory. Keys are sorted, so an unchanged
+// index is left untouched.
+func (i *CacheIndex) Save() error {
+	data, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache index: %w", err)
+	}
+	if _, err := writeFileAtomic(i.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+	return nil
+}
+
+// Provenance describes where a version's checksums came from.
+type Provenance struct {
+	// Source identifies the release source, e.g. https://github.com/golangci/golangci-lint.
+	Source string `json:"source"`
+	// ChecksumURL is the checksum file's download URL; empty if checksums were computed.
+	ChecksumURL string `json:"checksum_url,omitempty"`
+	// ChecksumSHA256 is the hex digest of the checksum file itself.
+	ChecksumSHA256 string `json:"checksum_file_sha256"`
+	// Origin is OriginNetwork or OriginCache.
+	Origin string `json:"origin"`
+	// FetchedAt is when the checksum file was downloaded, if known.
+	FetchedAt string `json:"fetched_at,omitempty"`
+	// Signature is one of the Signature* statuses.
+	Signature string `json:"signature"`
+}
+
+// newProvenance combines a cache entry with the source it was obtained from.
+func newProvenance(source string, entry CacheEntry) *Provenance {
+	return &Provenance{
+		Source:         source,
+		ChecksumURL:    entry.URL,
+		ChecksumSHA256: entry.SHA256,
+		Origin:         entry.Origin,
+		FetchedAt:      entry.FetchedAt,
+		Signature:      entry.Signature,
+	}
+}
+
+// sourceRepository identifies where a tool's releases come from, for provenance records.
+func sourceRepository(tool Tool) string {
+	if tool.Source == nil {
+		return "https://github.com/" + tool.Repo
+	}
+	switch tool.Source.Type {
+	case SourceHTTPIndex:
+		return tool.Source.URL
+	case "", SourceGitHub:
+		if tool.Source.URL == "" {
+			return "https://github.com/" + tool.Repo
+		}
+	}
+	return trimBaseURL(tool.Source.URL) + "/" + tool.Repo
+}
+
+// signatureStatus reports whether a release publishes a signature for its checksum file.
+func signatureStatus(release Release, checksumURL string) string {
+	if len(release.Assets) == 0 {
+		return SignatureUnknown
+	}
+	if checksumURL == "" {
+		return SignatureNone
+	}
+
+	name := path.Base(checksumURL)
+	for _, asset := range release.Assets {
+		for _, ext := range signatureExtensions {
+			if asset.Name == name+ext {
+				return SignatureNotVerified
+			}
+		}
+	}
+	return SignatureNone
+}
+
+// sha256Hex returns the hex SHA-256 digest of data.
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// fetchTime returns the current time for CacheEntry.FetchedAt.
+func fetchTime() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
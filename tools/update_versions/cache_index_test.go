@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheIndex_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	index, err := LoadCacheIndex(dir)
+	require.NoError(t, err, "LoadCacheIndex() should succeed without an index")
+	assert.Empty(t, index.Entries, "LoadCacheIndex() should return an empty index")
+
+	index.Entries["v2.6.1.txt"] = CacheEntry{URL: "https://example.com/v2.6.1.txt", SHA256: "abc", Origin: OriginNetwork, Signature: SignatureNone}
+	require.NoError(t, index.Save(), "Save() should succeed")
+
+	loaded, err := LoadCacheIndex(dir)
+	require.NoError(t, err, "LoadCacheIndex() should read the saved index")
+	assert.Equal(t, index.Entries, loaded.Entries, "LoadCacheIndex() should round-trip the entries")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, CacheIndexFile), []byte("{"), 0644))
+	_, err = LoadCacheIndex(dir)
+	assert.Error(t, err, "LoadCacheIndex() should reject a corrupt index")
+}
+
+func TestRunner_CacheIndexProvenance(t *testing.T) {
+	checksums := []byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n")
+	checksumURL := "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt"
+
+	t.Run("records downloads and reuses the entry on cache hits", func(t *testing.T) {
+		tempDir := t.TempDir()
+		mock := NewMockGitHubClient()
+		mock.AddAsset(checksumURL, checksums)
+		runner := NewRunner(Config{WorkspaceRoot: tempDir}, mock)
+		releases := []Release{{TagName: "v2.6.1"}}
+
+		index, err := LoadCacheIndex(tempDir)
+		require.NoError(t, err, "LoadCacheIndex() should succeed")
+		versions := runner.processReleases(context.Background(), DefaultTool(), mock, releases, tempDir, index)
+		require.Len(t, versions, 1, "processReleases() should return 1 version")
+
+		provenance := versions[0].Provenance
+		require.NotNil(t, provenance, "processReleases() should record provenance")
+		assert.Equal(t, "https://github.com/golangci/golangci-lint", provenance.Source, "provenance should name the source repository")
+		assert.Equal(t, checksumURL, provenance.ChecksumURL, "provenance should record the checksum file URL")
+		assert.Equal(t, sha256Hex(checksums), provenance.ChecksumSHA256, "provenance should hash the checksum file")
+		assert.Equal(t, OriginNetwork, provenance.Origin, "a downloaded file should come from the network")
+		assert.NotEmpty(t, provenance.FetchedAt, "a downloaded file should record its download time")
+		assert.Equal(t, SignatureUnknown, provenance.Signature, "signature status should be unknown without an asset list")
+
+		// A second run reads the file from the cache but reports the same provenance
+		again := runner.processReleases(context.Background(), DefaultTool(), mock, releases, tempDir, index)
+		require.Len(t, again, 1, "processReleases() should return 1 version")
+		assert.Equal(t, provenance, again[0].Provenance, "a cache hit should report the indexed provenance")
+	})
+
+	t.Run("backfills files cached without a record", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "v2.6.1.txt"), checksums, 0644))
+		mock := NewMockGitHubClient()
+		runner := NewRunner(Config{WorkspaceRoot: tempDir}, mock)
+
+		index, err := LoadCacheIndex(tempDir)
+		require.NoError(t, err, "LoadCacheIndex() should succeed")
+		versions := runner.processReleases(context.Background(), DefaultTool(), mock, []Release{{TagName: "v2.6.1"}}, tempDir, index)
+		require.Len(t, versions, 1, "processReleases() should return 1 version")
+
+		assert.Equal(t, &Provenance{
+			Source:         "https://github.com/golangci/golangci-lint",
+			ChecksumURL:    checksumURL,
+			ChecksumSHA256: sha256Hex(checksums),
+			Origin:         OriginCache,
+			Signature:      SignatureUnknown,
+		}, versions[0].Provenance, "an unrecorded cache file should be attributed to the cache")
+		assert.Contains(t, index.Entries, "v2.6.1.txt", "processReleases() should add the file to the index")
+	})
+}
+
+func TestSignatureStatus(t *testing.T) {
+	url := "https://example.com/v1/checksums.txt"
+
+	assert.Equal(t, SignatureUnknown, signatureStatus(Release{}, url), "a release without assets should be unknown")
+
+	unsigned := Release{Assets: []Asset{{Name: "checksums.txt"}, {Name: "tool-linux-amd64.tar.gz"}}}
+	assert.Equal(t, SignatureNone, signatureStatus(unsigned, url), "a release without a signature asset should be unsigned")
+	assert.Equal(t, SignatureNone, signatureStatus(unsigned, ""), "computed checksums should be unsigned")
+
+	for _, sig := range []string{"checksums.txt.sig", "checksums.txt.asc", "checksums.txt.sigstore.json"} {
+		signed := Release{Assets: []Asset{{Name: "checksums.txt"}, {Name: sig}}}
+		assert.Equal(t, SignatureNotVerified, signatureStatus(signed, url), "%s should mark the checksum file as signed", sig)
+	}
+}
+
+func TestSourceRepository(t *testing.T) {
+	assert.Equal(t, "https://github.com/golangci/golangci-lint", sourceRepository(DefaultTool()), "GitHub is the default source")
+
+	tool := Tool{Repo: "golangci/golangci-lint", Source: &SourceConfig{Type: SourceGitLab, URL: "https://gitlab.example.com/"}}
+	assert.Equal(t, "https://gitlab.example.com/golangci/golangci-lint", sourceRepository(tool), "forges should join the base URL and repository")
+
+	tool = Tool{Source: &SourceConfig{Type: SourceHTTPIndex, URL: "https://mirror.example.com/index.json"}}
+	assert.Equal(t, "https://mirror.example.com/index.json", sourceRepository(tool), "an HTTP index is its own source")
+}
+
+func TestGenerateOutput_Provenance(t *testing.T) {
+	provenance := &Provenance{
+		Source:         "https://github.com/golangci/golangci-lint",
+		ChecksumURL:    "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		ChecksumSHA256: "fff",
+		Origin:         OriginNetwork,
+		FetchedAt:      "2025-11-01T00:00:00Z",
+		Signature:      SignatureNone,
+	}
+	data := &TemplateData{
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "aaa"}}, Provenance: provenance},
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "bbb"}}},
+		},
+	}
+	dir := t.TempDir()
+
+	starlarkFile := filepath.Join(dir, "versions.bzl")
+	_, err := GenerateStarlarkFile(data, starlarkFile)
+	require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+	content, err := os.ReadFile(starlarkFile)
+	require.NoError(t, err, "Failed to read output")
+	assert.Contains(t, string(content), `GOLANGCI_PROVENANCE = {
+    "v2.6.1": {
+        "source": "https://github.com/golangci/golangci-lint",
+        "checksum_url": "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+        "checksum_file_sha256": "fff",
+        "origin": "network",
+        "fetched_at": "2025-11-01T00:00:00Z",
+        "signature": "unsigned",
+    },
+}`, "output should list the provenance of versions that have one")
+
+	jsonFile := filepath.Join(dir, "versions.json")
+	_, err = GenerateOutput(data, Output{Format: FormatJSON}, jsonFile)
+	require.NoError(t, err, "GenerateOutput() should succeed")
+	content, err = os.ReadFile(jsonFile)
+	require.NoError(t, err, "Failed to read output")
+
+	var doc JSONData
+	require.NoError(t, json.Unmarshal(content, &doc), "output should be valid JSON")
+	require.Len(t, doc.Versions, 2, "output should list both versions")
+	assert.Equal(t, provenance, doc.Versions[0].Provenance, "JSON output should carry the provenance")
+	assert.Nil(t, doc.Versions[1].Provenance, "JSON output should omit unknown provenance")
+}
+
+func TestRunner_DiscardsCorruptCacheFiles(t *testing.T) {
+	checksums := []byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n" +
+		"bbb2222222222222222222222222222222222222222222222222222222222222  golangci-lint-2.6.1-darwin-arm64.tar.gz\n")
+	checksumURL := "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt"
+
+	tests := []struct {
+		name   string
+		cached []byte
+		entry  *CacheEntry
+		assets []Asset
+	}{
+		{"truncated inside a digest", checksums[:100], nil, nil},
+		{"empty", []byte{}, nil, nil},
+		{"truncated at a line break", checksums[:len(checksums)/2], &CacheEntry{SHA256: sha256Hex(checksums), Origin: OriginNetwork}, nil},
+		{"truncated at a line break without an index entry", checksums[:len(checksums)/2], nil, []Asset{
+			{Name: "golangci-lint-2.6.1-checksums.txt", URL: checksumURL},
+			{Name: "golangci-lint-2.6.1-linux-amd64.tar.gz", URL: "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz"},
+			{Name: "golangci-lint-2.6.1-darwin-arm64.tar.gz", URL: "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-darwin-arm64.tar.gz"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			cacheFile := filepath.Join(tempDir, "v2.6.1.txt")
+			require.NoError(t, os.WriteFile(cacheFile, tt.cached, 0644))
+
+			index, err := LoadCacheIndex(tempDir)
+			require.NoError(t, err, "LoadCacheIndex() should succeed")
+			if tt.entry != nil {
+				index.Entries["v2.6.1.txt"] = *tt.entry
+			}
+
+			mock := NewMockGitHubClient()
+			mock.AddAsset(checksumURL, checksums)
+			versions := NewRunner(Config{WorkspaceRoot: tempDir}, mock).processReleases(context.Background(), DefaultTool(), mock, []Release{{TagName: "v2.6.1", Assets: tt.assets}}, tempDir, index)
+
+			require.Len(t, versions, 1, "processReleases() should return 1 version")
+			assert.Len(t, versions[0].Checksums, 2, "processReleases() should download the file again")
+			assert.Equal(t, OriginNetwork, versions[0].Provenance.Origin, "the file should come from the network")
+
+			cached, err := os.ReadFile(cacheFile)
+			require.NoError(t, err)
+			assert.Equal(t, checksums, cached, "processReleases() should replace the corrupt cache file")
+			assert.NoFileExists(t, cacheFile+".tmp", "the cache file should be written atomically")
+		})
+	}
+}
+
+func TestValidateChecksumFile(t *testing.T) {
+	valid := []byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n" +
+		"bbb2222222222222222222222222222222222222222222222222222222222222  golangci-lint-2.6.1-darwin-arm64.tar.gz\n")
+	firstLine := valid[:bytes.IndexByte(valid, '\n')+1]
+	assets := []Asset{
+		{Name: "golangci-lint-2.6.1-checksums.txt"},
+		{Name: "golangci-lint-2.6.1-linux-amd64.tar.gz"},
+		{Name: "golangci-lint-2.6.1-darwin-arm64.tar.gz"},
+		{Name: "golangci-lint-2.6.1-linux-amd64.deb"},
+	}
+
+	assert.NoError(t, ValidateChecksumFile(valid, defaultAssetPattern, nil), "a complete file should be valid")
+	assert.NoError(t, ValidateChecksumFile(valid, defaultAssetPattern, assets), "a file covering every archive should be valid")
+	assert.NoError(t, ValidateChecksumFile(valid[:len(valid)-1], defaultAssetPattern, assets), "a missing final newline should be accepted")
+	assert.Error(t, ValidateChecksumFile(valid[:40], defaultAssetPattern, nil), "a file cut inside a digest should be invalid")
+	assert.Error(t, ValidateChecksumFile([]byte("aaa1111111111111111111111111111111111111111111111111111111111111  README.md\n"), defaultAssetPattern, nil),
+		"a file without platforms should be invalid")
+	assert.Error(t, ValidateChecksumFile(append([]byte("not a checksum line\n"), valid...), defaultAssetPattern, nil),
+		"a file with a malformed line should be invalid")
+
+	// A file cut at a line break only shows against the release's archives
+	assert.NoError(t, ValidateChecksumFile(firstLine, defaultAssetPattern, nil), "whole-line truncation cannot be detected without assets")
+	assert.Error(t, ValidateChecksumFile(firstLine, defaultAssetPattern, assets), "a file missing an archive's checksum should be invalid")
+}
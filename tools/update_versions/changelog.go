@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// Changelog summarises how a run changed a tool's version data.
+type Changelog struct {
+	Tool            string
+	Added           []string
+	Removed         []string
+	PreviousDefault string
+	Default         string
+	Platforms       []PlatformChange
+	Checksums       []ChecksumChange
+}
+
+// PlatformChange lists the platforms a version kept in both runs gained or lost.
+type PlatformChange struct {
+	Version string
+	Gained  []string // "os/arch"
+	Lost    []string
+}
+
+// ChecksumChange is a platform whose SHA-256 differs between runs.
+type ChecksumChange struct {
+	Version  string
+	Platform string // "os/arch"
+	Previous string
+	Current  string
+}
+
+// NewChangelog compares the previous and current version data of a tool.
+// A nil previous means there was no earlier output, so every version is added.
+func NewChangelog(tool string, previous, current *TemplateData) *Changelog {
+	if previous == nil {
+		previous = &TemplateData{}
+	}
+
+	c := &Changelog{
+		Tool:            tool,
+		PreviousDefault: previous.DefaultVersion,
+		Default:         current.DefaultVersion,
+	}
+
+	old := make(map[string]VersionData, len(previous.Versions))
+	for _, v := range previous.Versions {
+		old[v.Tag] = v
+	}
+	kept := make(map[string]bool, len(current.Versions))
+
+	for _, v := range current.Versions {
+		prev, ok := old[v.Tag]
+		if !ok {
+			c.Added = append(c.Added, v.Tag)
+			continue
+		}
+		kept[v.Tag] = true
+
+		oldPlatforms := flattenChecksums(prev.ChecksumsByOS)
+		newPlatforms := flattenChecksums(v.ChecksumsByOS)
+
+		change := PlatformChange{Version: v.Tag}
+		for _, p := range sortedKeys(newPlatforms) {
+			oldHash, ok := oldPlatforms[p]
+			switch {
+			case !ok:
+				change.Gained = append(change.Gained, p)
+			case oldHash != newPlatforms[p]:
+				c.Checksums = append(c.Checksums, ChecksumChange{Version: v.Tag, Platform: p, Previous: oldHash, Current: newPlatforms[p]})
+			}
+		}
+		for _, p := range sortedKeys(oldPlatforms) {
+			if _, ok := newPlatforms[p]; !ok {
+				change.Lost = append(change.Lost, p)
+			}
+		}
+		if len(change.Gained) > 0 || len(change.Lost) > 0 {
+			c.Platforms = append(c.Platforms, change)
+		}
+	}
+
+	for _, v := range previous.Versions {
+		if !kept[v.Tag] {
+			c.Removed = append(c.Removed, v.Tag)
+		}
+	}
+
+	return c
+}
+
+// HasChanges reports whether anything differs between the two runs.
+func (c *Changelog) HasChanges() bool {
+	return len(c.Added) > 0 || len(c.Removed) > 0 || c.PreviousDefault != c.Default ||
+		len(c.Platforms) > 0 || len(c.Checksums) > 0
+}
+
+// Markdown renders the changelog as a Markdown section suitable for a PR description.
+func (c *Changelog) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", c.Tool)
+
+	if !c.HasChanges() {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+
+	switch {
+	case c.PreviousDefault == "":
+		fmt.Fprintf(&b, "Default version: `%s`\n\n", c.Default)
+	case c.PreviousDefault != c.Default:
+		fmt.Fprintf(&b, "Default version: `%s` → `%s`\n\n", c.PreviousDefault, c.Default)
+	}
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "### %s\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- `%s`\n", item)
+		}
+		b.WriteString("\n")
+	}
+	writeList("Added versions", c.Added)
+	writeList("Removed versions", c.Removed)
+
+	if len(c.Platforms) > 0 {
+		b.WriteString("### Platform changes\n\n")
+		for _, p := range c.Platforms {
+			var parts []string
+			if len(p.Gained) > 0 {
+				parts = append(parts, "gained "+codeList(p.Gained))
+			}
+			if len(p.Lost) > 0 {
+				parts = append(parts, "lost "+codeList(p.Lost))
+			}
+			fmt.Fprintf(&b, "- `%s`: %s\n", p.Version, strings.Join(parts, "; "))
+		}
+		b.WriteString("\n")
+	}
+
+	if len(c.Checksums) > 0 {
+		b.WriteString("### Changed checksums\n\n")
+		b.WriteString("| Version | Platform | Previous | Current |\n")
+		b.WriteString("| ------- | -------- | -------- | ------- |\n")
+		for _, h := range c.Checksums {
+			fmt.Fprintf(&b, "| `%s` | `%s` | `%s` | `%s` |\n", h.Version, h.Platform, h.Previous, h.Current)
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// RenderChangelogs combines the changelogs of a run into one Markdown document.
+func RenderChangelogs(changelogs []*Changelog) string {
+	var b strings.Builder
+	b.WriteString("# Version update\n")
+	for _, c := range changelogs {
+		b.WriteString("\n")
+		b.WriteString(c.Markdown())
+	}
+	return b.String()
+}
+
+// LoadPreviousData reads the version data a tool's outputs currently hold, from its first
+// built-in starlark output or else its first json output. It returns nil if there is none yet.
+func LoadPreviousData(tool Tool, resolve func(string) string) (*TemplateData, error) {
+	for _, format := range []string{FormatStarlark, FormatJSON} {
+		for _, out := range tool.AllOutputs() {
+			if out.Format != format || out.Template != "" {
+				continue
+			}
+
+			content, err := os.ReadFile(resolve(out.Path))
+			if errors.Is(err, os.ErrNotExist) {
+				return nil, nil
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", out.Path, err)
+			}
+
+			if format == FormatJSON {
+				return parseJSONData(content)
+			}
+			return parseStarlarkData(filepath.Base(out.Path), content, tool.VarPrefix)
+		}
+	}
+	return nil, nil
+}
+
+// parseJSONData reads a document written by RenderJSON.
+func parseJSONData(content []byte) (*TemplateData, error) {
+	var doc JSONData
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	data := &TemplateData{ToolName: doc.Tool, GeneratedAt: doc.GeneratedAt, DefaultVersion: doc.DefaultVersion}
+	for _, v := range doc.Versions {
+		data.Versions = append(data.Versions, VersionData{Tag: v.Tag, GoVersion: v.GoVersion, ChecksumsByOS: v.Checksums})
+	}
+	return data, nil
+}
+
+// parseStarlarkData executes a file rendered from template.bzl.tmpl and reads back
+// DEFAULT_VERSION and <PREFIX>_VERSIONS.
+func parseStarlarkData(filename string, content []byte, varPrefix string) (*TemplateData, error) {
+	thread := &starlark.Thread{Name: "read " + filename}
+	globals, err := starlark.ExecFileOptions(bzlFileOptions, thread, filename, content, starlark.StringDict{
+		"fail": starlark.NewBuiltin("fail", starlarkFail),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %s: %w", filename, err)
+	}
+
+	data := &TemplateData{}
+	if v, ok := starlark.AsString(globals["DEFAULT_VERSION"]); ok {
+		data.DefaultVersion = v
+	}
+
+	versions, ok := globals[varPrefix+"_VERSIONS"].(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("%s does not define %s_VERSIONS", filename, varPrefix)
+	}
+	for _, item := range versions.Items() {
+		tag, _ := starlark.AsString(item[0])
+		checksums, err := toChecksumMap(item[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s_VERSIONS[%q]: %w", varPrefix, tag, err)
+		}
+		data.Versions = append(data.Versions, VersionData{Tag: tag, ChecksumsByOS: checksums})
+	}
+
+	return data, nil
+}
+
+// toChecksumMap converts a Starlark {os: {arch: sha256}} dict.
+func toChecksumMap(v starlark.Value) (map[string]map[string]string, error) {
+	byOS, ok := v.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("expected dict, got %s", v.Type())
+	}
+
+	result := make(map[string]map[string]string)
+	for _, osItem := range byOS.Items() {
+		goos, _ := starlark.AsString(osItem[0])
+		byArch, ok := osItem[1].(*starlark.Dict)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected dict, got %s", goos, osItem[1].Type())
+		}
+		result[goos] = make(map[string]string)
+		for _, archItem := range byArch.Items() {
+			arch, _ := starlark.AsString(archItem[0])
+			hash, _ := starlark.AsString(archItem[1])
+			result[goos][arch] = hash
+		}
+	}
+	return result, nil
+}
+
+// flattenChecksums converts os -> arch -> sha256 into "os/arch" -> sha256.
+func flattenChecksums(byOS map[string]map[string]string) map[string]string {
+	flat := make(map[string]string)
+	for goos, archs := range byOS {
+		for arch, hash := range archs {
+			flat[goos+"/"+arch] = hash
+		}
+	}
+	return flat
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// codeList formats items as comma-separated inline code.
+func codeList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = "`" + item + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// WriteChangelog writes the Markdown changelog to each destination. The file is
+// overwritten; the step summary is appended to, as GitHub Actions expects.
+func WriteChangelog(markdown, file, stepSummary string) error {
+	if file != "" {
+		if err := os.WriteFile(file, []byte(markdown), 0644); err != nil {
+			return fmt.Errorf("failed to write changelog: %w", err)
+		}
+	}
+
+	if stepSummary != "" {
+		f, err := os.OpenFile(stepSummary, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open step summary: %w", err)
+		}
+		if _, err := f.WriteString(markdown); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("failed to write step summary: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("failed to write step summary: %w", err)
+		}
+	}
+
+	return nil
+}
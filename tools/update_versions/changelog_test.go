@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewChangelog(t *testing.T) {
+	previous := &TemplateData{
+		DefaultVersion: "v2.6.0",
+		Versions: []VersionData{
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{
+				"linux":   {"amd64": "aaa", "386": "bbb"},
+				"freebsd": {"amd64": "ccc"},
+			}},
+			{Tag: "v2.5.0", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "ddd"}}},
+		},
+	}
+	current := &TemplateData{
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "eee"}}},
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{
+				"linux": {"amd64": "fff", "riscv64": "ggg"},
+			}},
+		},
+	}
+
+	c := NewChangelog("golangci-lint", previous, current)
+
+	assert.Equal(t, []string{"v2.6.1"}, c.Added, "NewChangelog() should list added versions")
+	assert.Equal(t, []string{"v2.5.0"}, c.Removed, "NewChangelog() should list removed versions")
+	assert.Equal(t, []PlatformChange{{Version: "v2.6.0", Gained: []string{"linux/riscv64"}, Lost: []string{"freebsd/amd64", "linux/386"}}},
+		c.Platforms, "NewChangelog() should list platform changes of kept versions")
+	assert.Equal(t, []ChecksumChange{{Version: "v2.6.0", Platform: "linux/amd64", Previous: "aaa", Current: "fff"}},
+		c.Checksums, "NewChangelog() should list changed hashes")
+	assert.True(t, c.HasChanges(), "HasChanges() should report changes")
+
+	assert.Equal(t, "## golangci-lint\n"+
+		"\n"+
+		"Default version: `v2.6.0` → `v2.6.1`\n"+
+		"\n"+
+		"### Added versions\n"+
+		"\n"+
+		"- `v2.6.1`\n"+
+		"\n"+
+		"### Removed versions\n"+
+		"\n"+
+		"- `v2.5.0`\n"+
+		"\n"+
+		"### Platform changes\n"+
+		"\n"+
+		"- `v2.6.0`: gained `linux/riscv64`; lost `freebsd/amd64`, `linux/386`\n"+
+		"\n"+
+		"### Changed checksums\n"+
+		"\n"+
+		"| Version | Platform | Previous | Current |\n"+
+		"| ------- | -------- | -------- | ------- |\n"+
+		"| `v2.6.0` | `linux/amd64` | `aaa` | `fff` |\n",
+		c.Markdown(), "Markdown() should render every section")
+}
+
+func TestNewChangelog_NoPrevious(t *testing.T) {
+	current := &TemplateData{DefaultVersion: "v2.6.1", Versions: []VersionData{{Tag: "v2.6.1"}, {Tag: "v2.6.0"}}}
+
+	c := NewChangelog("golangci-lint", nil, current)
+
+	assert.Equal(t, []string{"v2.6.1", "v2.6.0"}, c.Added, "NewChangelog() should treat every version as added")
+	assert.Contains(t, c.Markdown(), "Default version: `v2.6.1`\n", "Markdown() should show the initial default")
+}
+
+func TestNewChangelog_NoChanges(t *testing.T) {
+	data := &TemplateData{
+		DefaultVersion: "v2.6.1",
+		Versions:       []VersionData{{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "aaa"}}}},
+	}
+
+	c := NewChangelog("golangci-lint", data, data)
+
+	assert.False(t, c.HasChanges(), "HasChanges() should be false for identical data")
+	assert.Equal(t, "## golangci-lint\n\nNo changes.\n", c.Markdown(), "Markdown() should say there are no changes")
+}
+
+func TestLoadPreviousData(t *testing.T) {
+	data := &TemplateData{
+		ToolName:       "golangci-lint",
+		VarPrefix:      "GOLANGCI",
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "aaa"}, "darwin": {"arm64": "bbb"}}},
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{"windows": {"amd64": "ccc"}}},
+		},
+	}
+	tempDir := t.TempDir()
+	resolve := func(p string) string { return filepath.Join(tempDir, p) }
+
+	t.Run("starlark output", func(t *testing.T) {
+		_, err := GenerateStarlarkFile(data, resolve("versions.bzl"))
+		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+
+		tool := Tool{VarPrefix: "GOLANGCI", Outputs: []Output{{Path: "versions.json", Format: FormatJSON}, {Path: "versions.bzl", Format: FormatStarlark}}}
+		previous, err := LoadPreviousData(tool, resolve)
+		require.NoError(t, err, "LoadPreviousData() should succeed")
+		require.NotNil(t, previous, "LoadPreviousData() should return data")
+
+		assert.Equal(t, "v2.6.1", previous.DefaultVersion, "LoadPreviousData() should read DEFAULT_VERSION")
+		assert.Equal(t, []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: data.Versions[0].ChecksumsByOS},
+			{Tag: "v2.6.0", ChecksumsByOS: data.Versions[1].ChecksumsByOS},
+		}, previous.Versions, "LoadPreviousData() should prefer the starlark output and keep version order")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		_, err := GenerateJSONFile(data, resolve("versions.json"))
+		require.NoError(t, err, "GenerateJSONFile() should succeed")
+
+		tool := Tool{VarPrefix: "GOLANGCI", Outputs: []Output{{Path: "versions.json", Format: FormatJSON}}}
+		previous, err := LoadPreviousData(tool, resolve)
+		require.NoError(t, err, "LoadPreviousData() should succeed")
+		require.NotNil(t, previous, "LoadPreviousData() should return data")
+		assert.Len(t, previous.Versions, 2, "LoadPreviousData() should read versions from JSON")
+	})
+
+	t.Run("no previous output", func(t *testing.T) {
+		tool := Tool{VarPrefix: "GOLANGCI", OutputFile: "missing/versions.bzl"}
+		previous, err := LoadPreviousData(tool, resolve)
+		require.NoError(t, err, "LoadPreviousData() should succeed without a previous file")
+		assert.Nil(t, previous, "LoadPreviousData() should return nil without a previous file")
+	})
+
+	t.Run("unreadable starlark", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(resolve("broken.bzl"), []byte("X = {"), 0644), "Failed to write file")
+
+		tool := Tool{VarPrefix: "GOLANGCI", OutputFile: "broken.bzl"}
+		_, err := LoadPreviousData(tool, resolve)
+		assert.Error(t, err, "LoadPreviousData() should report invalid Starlark")
+	})
+}
+
+func TestWriteChangelog(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "CHANGES.md")
+	summary := filepath.Join(tempDir, "summary.md")
+	require.NoError(t, os.WriteFile(summary, []byte("earlier step\n"), 0644), "Failed to write summary")
+
+	require.NoError(t, WriteChangelog("# Version update\n", file, summary), "WriteChangelog() should succeed")
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err, "Failed to read changelog")
+	assert.Equal(t, "# Version update\n", string(content), "WriteChangelog() should write the file")
+
+	content, err = os.ReadFile(summary)
+	require.NoError(t, err, "Failed to read summary")
+	assert.Equal(t, "earlier step\n# Version update\n", string(content), "WriteChangelog() should append to the step summary")
+}
+
+func TestRunner_Run_Changelog(t *testing.T) {
+	tempDir := t.TempDir()
+	checksumURL := "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt"
+
+	var stdout bytes.Buffer
+	config := Config{
+		Count:           1,
+		CacheDir:        filepath.Join(tempDir, "cache"),
+		OutputFile:      "versions.bzl",
+		WorkspaceRoot:   tempDir,
+		ChangelogWriter: &stdout,
+		ChangelogFile:   "CHANGES.md",
+		StepSummaryFile: filepath.Join(tempDir, "summary.md"),
+	}
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(checksumURL, []byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"))
+	require.NoError(t, NewRunner(config, mock).Run(context.Background()), "first Runner.Run() should succeed")
+	assert.Contains(t, stdout.String(), "- `v2.6.1`", "first run should list the version as added")
+
+	// A re-published release with a new hash and an extra platform
+	stdout.Reset()
+	require.NoError(t, os.RemoveAll(config.CacheDir), "Failed to clear cache")
+	mock.AddAsset(checksumURL, []byte(
+		"bbb2222222222222222222222222222222222222222222222222222222222222  golangci-lint-2.6.1-linux-amd64.tar.gz\n"+
+			"ccc3333333333333333333333333333333333333333333333333333333333333  golangci-lint-2.6.1-darwin-arm64.tar.gz\n"))
+	require.NoError(t, NewRunner(config, mock).Run(context.Background()), "second Runner.Run() should succeed")
+
+	markdown := stdout.String()
+	assert.Contains(t, markdown, "gained `darwin/arm64`", "changelog should list gained platforms")
+	assert.Contains(t, markdown, "| `v2.6.1` | `linux/amd64` | `aaa1111111111111111111111111111111111111111111111111111111111111` |",
+		"changelog should list changed hashes")
+	assert.NotContains(t, markdown, "### Added versions", "changelog should not list kept versions as added")
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "CHANGES.md"))
+	require.NoError(t, err, "Runner.Run() should write the changelog file")
+	assert.Equal(t, markdown, string(content), "changelog file should match stdout")
+
+	summary, err := os.ReadFile(config.StepSummaryFile)
+	require.NoError(t, err, "Runner.Run() should write the step summary")
+	assert.Contains(t, string(summary), "gained `darwin/arm64`", "step summary should contain the latest changelog")
+}
@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"fmt"
 	"log"
-	"regexp"
 	"strings"
 )
 
@@ -19,10 +18,30 @@ type Platform struct {
 type Version struct {
 	Tag       string
 	Checksums map[Platform]string
+	// GoVersion is the Go toolchain version the release was built with, if recorded.
+	GoVersion string
+	// License is the SPDX identifier of the license shipped in the release archive, if
+	// recorded; NoAssertion if the archive has none or it was not recognized.
+	License string
+	// Provenance records where the checksums came from, if known.
+	Provenance *Provenance
+	// OlderMajor is set for the newest release of an older major line kept beyond the
+	// --count window (see selectReleases).
+	OlderMajor bool
 }
 
+// archiveExtensions lists the file extensions treated as release archives.
+var archiveExtensions = []string{".tar.gz", ".tar.xz", ".tgz", ".zip"}
+
 // ParseChecksumFile parses a SHA-256 checksum file and returns a map of platforms to checksums.
+// File names are matched against DefaultAssetPattern.
 func ParseChecksumFile(content []byte) (map[Platform]string, error) {
+	return ParseChecksumFileWithPattern(content, defaultAssetPattern)
+}
+
+// ParseChecksumFileWithPattern parses a SHA-256 checksum file, extracting platforms
+// from file names using the given asset pattern.
+func ParseChecksumFileWithPattern(content []byte, pattern *AssetPattern) (map[Platform]string, error) {
 	checksums := make(map[Platform]string)
 	scanner := bufio.NewScanner(bytes.NewReader(content))
 
@@ -50,15 +69,13 @@ func ParseChecksumFile(content []byte) (map[Platform]string, error) {
 			continue
 		}
 
-		// Only process .tar.gz and .zip files
-		if !strings.HasSuffix(filename, ".tar.gz") && !strings.HasSuffix(filename, ".zip") {
-			continue
-		}
-
-		// Extract platform from filename
-		platform, err := ExtractPlatformFromFilename(filename)
+		// Extract platform from filename. Non-archive files (packages, signatures)
+		// are expected not to match and are skipped silently.
+		platform, err := pattern.ExtractPlatform(filename)
 		if err != nil {
-			log.Printf("Warning: skipping file %s: %v", filename, err)
+			if isArchive(filename) {
+				log.Printf("Warning: skipping file %s: %v", filename, err)
+			}
 			continue
 		}
 
@@ -72,25 +89,57 @@ func ParseChecksumFile(content []byte) (map[Platform]string, error) {
 	return checksums, nil
 }
 
-// ExtractPlatformFromFilename extracts OS and architecture from a filename.
-// Expected format: golangci-lint-{version}-{os}-{arch}.{tar.gz|zip}.
-func ExtractPlatformFromFilename(filename string) (*Platform, error) {
-	// Pattern: golangci-lint-{version}-{os}-{arch}.tar.gz or .zip
-	// Example: golangci-lint-2.6.1-linux-amd64.tar.gz
-	pattern := regexp.MustCompile(`golangci-lint-[\d.]+-([\w]+)-([\w]+)\.(tar\.gz|zip)`)
-	matches := pattern.FindStringSubmatch(filename)
+// ValidateChecksumFile checks that a cached checksum file parses cleanly: every line must be
+// a whole "<sha256>  <file>" entry, which a file cut off inside a digest does not end with,
+// and it must yield at least one platform. A file cut at a line break parses cleanly, so
+// when the release lists its assets, every archive among them must have a checksum too.
+func ValidateChecksumFile(content []byte, pattern *AssetPattern, assets []Asset) error {
+	for i, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !isValidSHA256(fields[0]) {
+			return fmt.Errorf("checksum file is truncated or malformed at line %d", i+1)
+		}
+	}
 
-	if len(matches) != 4 {
-		return nil, fmt.Errorf("filename does not match expected pattern: %s", filename)
+	checksums, err := ParseChecksumFileWithPattern(content, pattern)
+	if err != nil {
+		return err
+	}
+	if len(checksums) == 0 {
+		return fmt.Errorf("checksum file lists no platforms")
 	}
 
-	os := matches[1]
-	arch := matches[2]
+	platforms := make(map[Platform]bool)
+	for _, a := range archiveAssets(assets, pattern) {
+		platform, _ := pattern.ExtractPlatform(a.Name)
+		platforms[*platform] = true
+	}
+	for platform := range platforms {
+		if _, ok := checksums[platform]; !ok {
+			return fmt.Errorf("checksum file lists %d platforms but the release has archives for %d", len(checksums), len(platforms))
+		}
+	}
+	return nil
+}
 
-	return &Platform{
-		OS:   os,
-		Arch: arch,
-	}, nil
+// ExtractPlatformFromFilename extracts OS and architecture from a filename.
+// Expected format: golangci-lint-{version}-{os}-{arch}.{tar.gz|zip}, where
+// version may carry prerelease or build metadata (e.g. 2.7.0-rc.1).
+func ExtractPlatformFromFilename(filename string) (*Platform, error) {
+	return defaultAssetPattern.ExtractPlatform(filename)
+}
+
+// isArchive reports whether filename has one of the known archive extensions.
+func isArchive(filename string) bool {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(filename, ext) {
+			return true
+		}
+	}
+	return false
 }
 
 // isValidSHA256 checks if a string is a valid SHA-256 hash (64 hex characters).
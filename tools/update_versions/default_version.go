@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Default version policies. Any other value of --default-version names a tag or alias.
+const (
+	// DefaultVersionLatestStable selects the newest stable version by semver.
+	DefaultVersionLatestStable = "latest-stable"
+	// DefaultVersionPreviousMinor selects the newest stable version of the minor line before
+	// the latest, giving new minors soak time before they become the default.
+	DefaultVersionPreviousMinor = "previous-minor"
+	// DefaultVersionOldestSupported selects the oldest stable version that is still processed.
+	DefaultVersionOldestSupported = "oldest-supported"
+)
+
+// ResolveDefaultVersion picks the default version of versions according to policy, which is
+// one of the DefaultVersion* policies or a tag or alias. An empty policy is latest-stable.
+// When no version is stable, the policies consider prereleases too. The policies only
+// consider the --count window: releases kept for an older major line (OlderMajor) can be
+// named as a tag or alias but are never a policy's pick.
+func ResolveDefaultVersion(policy string, versions []VersionData) (string, error) {
+	if len(versions) == 0 {
+		return "", nil
+	}
+
+	window := windowVersions(versions)
+	candidates := stableVersions(window)
+	if len(candidates) == 0 {
+		candidates = window
+	}
+
+	switch policy {
+	case "", DefaultVersionLatestStable:
+		return newestVersion(candidates), nil
+
+	case DefaultVersionOldestSupported:
+		oldest := candidates[0].Tag
+		for _, v := range candidates[1:] {
+			if CompareVersions(v.Tag, oldest) < 0 {
+				oldest = v.Tag
+			}
+		}
+		return oldest, nil
+
+	case DefaultVersionPreviousMinor:
+		latest := newestVersion(candidates)
+		_, latestMinor, ok := versionLine(latest)
+		if !ok {
+			return "", fmt.Errorf("default version %s: %s has no minor line", policy, latest)
+		}
+		var previous []VersionData
+		for _, v := range candidates {
+			if _, minor, ok := versionLine(v.Tag); ok && CompareVersions(minor, latestMinor) < 0 {
+				previous = append(previous, v)
+			}
+		}
+		if len(previous) == 0 {
+			return "", fmt.Errorf("default version %s: no processed version precedes the %s line; raise --count", policy, latestMinor)
+		}
+		return newestVersion(previous), nil
+	}
+
+	for _, v := range versions {
+		if v.Tag == policy {
+			return policy, nil
+		}
+	}
+	for _, a := range ComputeAliases(versions) {
+		if a.Name == policy {
+			return a.Version, nil
+		}
+	}
+	return "", fmt.Errorf("default version %q is neither a policy (%s) nor one of the %d processed versions (%s)",
+		policy, strings.Join(DefaultVersionPolicies(), ", "), len(versions), strings.Join(versionTags(versions), ", "))
+}
+
+// DefaultVersionPolicies lists the named default version policies.
+func DefaultVersionPolicies() []string {
+	return []string{DefaultVersionLatestStable, DefaultVersionPreviousMinor, DefaultVersionOldestSupported}
+}
+
+// ApplyDefaultVersion sets DefaultVersion according to policy (see ResolveDefaultVersion)
+// and recomputes the per-major defaults so they agree with it.
+func (d *TemplateData) ApplyDefaultVersion(policy string) error {
+	version, err := ResolveDefaultVersion(policy, d.Versions)
+	if err != nil {
+		return err
+	}
+	d.DefaultVersion = version
+	d.DefaultVersions = ComputeDefaultVersions(version, d.Versions)
+	return nil
+}
+
+// windowVersions returns the versions that are not OlderMajor, or all of them if every
+// version is.
+func windowVersions(versions []VersionData) []VersionData {
+	var window []VersionData
+	for _, v := range versions {
+		if !v.OlderMajor {
+			window = append(window, v)
+		}
+	}
+	if len(window) == 0 {
+		return versions
+	}
+	return window
+}
+
+// stableVersions returns the versions without a prerelease.
+func stableVersions(versions []VersionData) []VersionData {
+	var stable []VersionData
+	for _, v := range versions {
+		if _, pre := splitVersion(v.Tag); pre == nil {
+			stable = append(stable, v)
+		}
+	}
+	return stable
+}
+
+// newestVersion returns the highest tag of a non-empty list by semver.
+func newestVersion(versions []VersionData) string {
+	newest := versions[0].Tag
+	for _, v := range versions[1:] {
+		if CompareVersions(v.Tag, newest) > 0 {
+			newest = v.Tag
+		}
+	}
+	return newest
+}
+
+// versionTags returns the tags of versions in order.
+func versionTags(versions []VersionData) []string {
+	tags := make([]string, len(versions))
+	for i, v := range versions {
+		tags[i] = v.Tag
+	}
+	return tags
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDefaultVersion(t *testing.T) {
+	// Deliberately not in release order, with a prerelease first
+	versions := []VersionData{
+		{Tag: "v2.7.0-rc.1"},
+		{Tag: "v2.5.2"},
+		{Tag: "v2.6.1"},
+		{Tag: "v2.6.0"},
+		{Tag: "v2.5.3"},
+		{Tag: "v1.64.8"},
+	}
+
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"", "v2.6.1"},
+		{DefaultVersionLatestStable, "v2.6.1"},
+		{DefaultVersionPreviousMinor, "v2.5.3"},
+		{DefaultVersionOldestSupported, "v1.64.8"},
+		{"v2.6.0", "v2.6.0"},
+		{"v2.7.0-rc.1", "v2.7.0-rc.1"},
+		{"v2.5", "v2.5.3"},
+		{"v1", "v1.64.8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			got, err := ResolveDefaultVersion(tt.policy, versions)
+			require.NoError(t, err, "ResolveDefaultVersion() should succeed")
+			assert.Equal(t, tt.want, got, "ResolveDefaultVersion() returned unexpected version")
+		})
+	}
+}
+
+func TestResolveDefaultVersion_SelectedReleases(t *testing.T) {
+	releases := []Release{{TagName: "v2.6.1"}, {TagName: "v2.6.0"}, {TagName: "v2.5.3"}, {TagName: "v1.64.8"}, {TagName: "v1.64.7"}}
+	selected := func(count int) []VersionData {
+		var versions []VersionData
+		for _, r := range selectReleases(releases, count) {
+			versions = append(versions, VersionData{Tag: r.TagName, OlderMajor: r.OlderMajor})
+		}
+		return versions
+	}
+
+	tests := []struct {
+		count  int
+		policy string
+		want   string
+	}{
+		{2, DefaultVersionLatestStable, "v2.6.1"},
+		{2, DefaultVersionOldestSupported, "v2.6.0"},
+		{3, DefaultVersionPreviousMinor, "v2.5.3"},
+		{3, DefaultVersionOldestSupported, "v2.5.3"},
+		{2, "v1.64.8", "v1.64.8"},
+		{2, "v1", "v1.64.8"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveDefaultVersion(tt.policy, selected(tt.count))
+		require.NoError(t, err, "ResolveDefaultVersion(%q) should succeed with count %d", tt.policy, tt.count)
+		assert.Equal(t, tt.want, got, "ResolveDefaultVersion(%q) with count %d should only pick older majors by name", tt.policy, tt.count)
+	}
+
+	_, err := ResolveDefaultVersion(DefaultVersionPreviousMinor, selected(2))
+	assert.Error(t, err, "previous-minor should not fall back to an older major line outside the window")
+}
+
+func TestResolveDefaultVersion_Errors(t *testing.T) {
+	_, err := ResolveDefaultVersion("v2.4.0", []VersionData{{Tag: "v2.6.1"}, {Tag: "v2.5.0"}})
+	require.Error(t, err, "ResolveDefaultVersion() should reject an unprocessed tag")
+	assert.Contains(t, err.Error(), "v2.6.1, v2.5.0", "error should list the processed versions")
+
+	_, err = ResolveDefaultVersion(DefaultVersionPreviousMinor, []VersionData{{Tag: "v2.6.1"}, {Tag: "v2.6.0"}})
+	assert.Error(t, err, "previous-minor should fail when only one minor line is processed")
+}
+
+func TestResolveDefaultVersion_OnlyPrereleases(t *testing.T) {
+	versions := []VersionData{{Tag: "v3.0.0-beta.1"}, {Tag: "v3.0.0-beta.2"}}
+
+	got, err := ResolveDefaultVersion(DefaultVersionLatestStable, versions)
+	require.NoError(t, err, "ResolveDefaultVersion() should succeed")
+	assert.Equal(t, "v3.0.0-beta.2", got, "latest-stable should fall back to the newest prerelease")
+
+	got, err = ResolveDefaultVersion("", nil)
+	require.NoError(t, err, "ResolveDefaultVersion() should succeed without versions")
+	assert.Empty(t, got, "ResolveDefaultVersion() should return no default without versions")
+}
+
+func TestRunner_Run_DefaultVersionPolicy(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mock := NewMockGitHubClient()
+	for _, tag := range []string{"v2.6.1", "v2.6.0", "v2.5.0"} {
+		mock.AddRelease(tag)
+		version := tag[1:]
+		mock.AddAsset(
+			"https://github.com/golangci/golangci-lint/releases/download/"+tag+"/golangci-lint-"+version+"-checksums.txt",
+			[]byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-"+version+"-linux-amd64.tar.gz\n"),
+		)
+	}
+
+	tool := DefaultTool()
+	tool.DefaultVersion = DefaultVersionPreviousMinor
+	runner := NewRunner(Config{
+		Count:         3,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{tool},
+		OmitTimestamp: true,
+	}, mock)
+	require.NoError(t, runner.Run(context.Background()), "Runner.Run() should succeed")
+
+	content, err := os.ReadFile(filepath.Join(tempDir, tool.OutputFile))
+	require.NoError(t, err, "Failed to read output")
+	assert.Contains(t, string(content), `DEFAULT_VERSION = "v2.5.0"`, "DEFAULT_VERSION should follow the policy")
+
+	tool.DefaultVersion = "v2.4.0"
+	runner = NewRunner(Config{Count: 3, CacheDir: filepath.Join(tempDir, "cache"), WorkspaceRoot: tempDir, Tools: []Tool{tool}}, mock)
+	assert.Error(t, runner.Run(context.Background()), "Runner.Run() should reject a default outside the processed versions")
+}
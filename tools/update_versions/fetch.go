@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.starlark.net/starlark"
+)
+
+// FetchTarget is the release archive of one version on one platform, resolved from
+// generated version data.
+type FetchTarget struct {
+	Version string
+	OS      string
+	Arch    string
+	SHA256  string
+}
+
+// ResolveFetchTarget executes a file rendered from template.bzl.tmpl and resolves version
+// (a tag, an alias, or empty for the default) for a Go platform the way the module
+// extension does: through get_<prefix>_version_info, and by matching the platform's
+// constraints against <PREFIX>_PLATFORMS.
+func ResolveFetchTarget(filename string, content []byte, varPrefix, version, goos, goarch string) (*FetchTarget, error) {
+	thread := &starlark.Thread{Name: "read " + filename}
+	globals, err := starlark.ExecFileOptions(bzlFileOptions, thread, filename, content, starlark.StringDict{
+		"fail": starlark.NewBuiltin("fail", starlarkFail),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate %s: %w", filename, err)
+	}
+
+	funcName := "get_" + strings.ToLower(varPrefix) + "_version_info"
+	fn, ok := globals[funcName].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("%s does not define %s", filename, funcName)
+	}
+	var arg starlark.Value = starlark.None
+	if version != "" {
+		arg = starlark.String(version)
+	}
+	result, err := starlark.Call(thread, fn, starlark.Tuple{arg}, nil)
+	if err != nil {
+		return nil, err
+	}
+	tuple, ok := result.(starlark.Tuple)
+	if !ok || len(tuple) != 2 {
+		return nil, fmt.Errorf("%s() returned %s, want (version, checksums)", funcName, result.Type())
+	}
+	tag, _ := starlark.AsString(tuple[0])
+	checksums, err := toChecksumMap(tuple[1])
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", funcName, err)
+	}
+
+	want, err := Constraints(goos, goarch)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported platform %s/%s: %w", goos, goarch, err)
+	}
+	platforms, ok := globals[varPrefix+"_PLATFORMS"].(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("%s does not define %s_PLATFORMS", filename, varPrefix)
+	}
+	for _, item := range platforms.Items() {
+		platform, ok := item[1].(*starlark.Dict)
+		if !ok {
+			continue
+		}
+		constraints, _, _ := platform.Get(starlark.String("constraints"))
+		if !sameStrings(constraints, want) {
+			continue
+		}
+
+		osValue, _, _ := platform.Get(starlark.String("os"))
+		archValue, _, _ := platform.Get(starlark.String("arch"))
+		relOS, _ := starlark.AsString(osValue)
+		relArch, _ := starlark.AsString(archValue)
+		sha256, ok := checksums[relOS][relArch]
+		if !ok {
+			return nil, fmt.Errorf("%s has no %s-%s release", tag, relOS, relArch)
+		}
+		return &FetchTarget{Version: tag, OS: relOS, Arch: relArch, SHA256: sha256}, nil
+	}
+	return nil, fmt.Errorf("no release platform matches %s", strings.Join(want, ", "))
+}
+
+// sameStrings reports whether a Starlark list holds exactly the strings want, in order.
+func sameStrings(v starlark.Value, want []string) bool {
+	list, ok := v.(*starlark.List)
+	if !ok || list.Len() != len(want) {
+		return false
+	}
+	for i, w := range want {
+		if s, ok := starlark.AsString(list.Index(i)); !ok || s != w {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultFetchDir returns the per-user directory the fetch subcommand installs into.
+func DefaultFetchDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "update_versions"), nil
+}
+
+// Fetch installs the binary of a tool version for a Go platform below destRoot and returns
+// its path. The version is resolved from the tool's generated Starlark file; the archive is
+// downloaded from the tool's release source, verified against the pinned SHA-256 and
+// extracted to <destRoot>/<tool>/<version>/<os>_<arch>. An existing installation is reused.
+func (r *Runner) Fetch(ctx context.Context, tool Tool, version, goos, goarch, destRoot string) (string, error) {
+	versionsFile := ""
+	for _, out := range tool.AllOutputs() {
+		if (out.Format == "" || out.Format == FormatStarlark) && out.Template == "" {
+			versionsFile = r.resolvePath(out.Path)
+			break
+		}
+	}
+	if versionsFile == "" {
+		return "", fmt.Errorf("%s has no starlark output to resolve versions from", tool.Name)
+	}
+	content, err := os.ReadFile(versionsFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read version data: %w", err)
+	}
+
+	target, err := ResolveFetchTarget(filepath.Base(versionsFile), content, tool.VarPrefix, version, goos, goarch)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateTag(target.Version); err != nil {
+		return "", err
+	}
+
+	binary := tool.Name
+	if target.OS == "windows" {
+		binary += ".exe"
+	}
+	dest := filepath.Join(destRoot, tool.Name, target.Version, target.OS+"_"+target.Arch)
+	binaryPath := filepath.Join(dest, binary)
+	if _, err := os.Stat(binaryPath); err == nil {
+		log.Printf("Using installed %s %s", tool.Name, target.Version)
+		return binaryPath, nil
+	}
+
+	source, err := r.sourceFor(tool)
+	if err != nil {
+		return "", fmt.Errorf("failed to create release source: %w", err)
+	}
+	url := RenderURL(assetURLTemplate(tool, source), target.Version, target.OS, target.Arch)
+	log.Printf("Downloading %s...", url)
+	archive, err := source.DownloadAsset(ctx, url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	sum := sha256.Sum256(archive)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, target.SHA256) {
+		return "", fmt.Errorf("checksum mismatch for %s: got %s, want %s", url, got, target.SHA256)
+	}
+
+	// Extract next to the destination and rename it into place, so an interrupted fetch
+	// never leaves a partial installation behind
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(dest), err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(dest), ".fetch-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	if err := extractArchive(url, archive, tmp, archiveRoot(url)); err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(tmp, binary)); err != nil {
+		return "", fmt.Errorf("archive %s has no %s", url, binary)
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		// Another fetch may have installed the same version meanwhile
+		if _, statErr := os.Stat(binaryPath); statErr != nil {
+			return "", fmt.Errorf("failed to install %s: %w", dest, errors.Join(err, statErr))
+		}
+	}
+	return binaryPath, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeFetchVersions generates versions.bzl for v2.6.1 and v2.6.0 below dir and returns
+// a tool reading it.
+func writeFetchVersions(t *testing.T, dir string, checksums map[string]string) Tool {
+	t.Helper()
+
+	data := &TemplateData{
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": checksums["v2.6.1"], "armv6": "a6"}, "windows": {"amd64": "w1"}}},
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": checksums["v2.6.0"]}}},
+		},
+	}
+	data.Aliases = ComputeAliases(data.Versions)
+	data.Platforms = ComputePlatforms(data.Versions)
+	require.NoError(t, data.ApplyDefaultVersion(DefaultVersionLatestStable), "ApplyDefaultVersion() should succeed")
+	_, err := GenerateStarlarkFile(data, filepath.Join(dir, "versions.bzl"))
+	require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+
+	tool := DefaultTool()
+	tool.OutputFile = "versions.bzl"
+	return tool
+}
+
+func TestResolveFetchTarget(t *testing.T) {
+	dir := t.TempDir()
+	writeFetchVersions(t, dir, map[string]string{"v2.6.1": "aaa", "v2.6.0": "bbb"})
+	content, err := os.ReadFile(filepath.Join(dir, "versions.bzl"))
+	require.NoError(t, err, "Failed to read versions.bzl")
+
+	target, err := ResolveFetchTarget("versions.bzl", content, "GOLANGCI", "", "linux", "amd64")
+	require.NoError(t, err, "ResolveFetchTarget() should resolve the default version")
+	assert.Equal(t, &FetchTarget{Version: "v2.6.1", OS: "linux", Arch: "amd64", SHA256: "aaa"}, target, "ResolveFetchTarget() should pick the default version")
+
+	target, err = ResolveFetchTarget("versions.bzl", content, "GOLANGCI", "v2.6.0", "linux", "amd64")
+	require.NoError(t, err, "ResolveFetchTarget() should resolve a tag")
+	assert.Equal(t, "bbb", target.SHA256, "ResolveFetchTarget() should return the tag's checksum")
+
+	target, err = ResolveFetchTarget("versions.bzl", content, "GOLANGCI", "v2", "linux", "arm")
+	require.NoError(t, err, "ResolveFetchTarget() should resolve an alias")
+	assert.Equal(t, &FetchTarget{Version: "v2.6.1", OS: "linux", Arch: "armv6", SHA256: "a6"}, target, "ResolveFetchTarget() should match GOARCH=arm to the armv6 release")
+
+	_, err = ResolveFetchTarget("versions.bzl", content, "GOLANGCI", "v2.6.0", "windows", "amd64")
+	assert.ErrorContains(t, err, "v2.6.0 has no windows-amd64 release", "ResolveFetchTarget() should reject a platform the version lacks")
+
+	_, err = ResolveFetchTarget("versions.bzl", content, "GOLANGCI", "v9.9.9", "linux", "amd64")
+	assert.Error(t, err, "ResolveFetchTarget() should reject an unknown version")
+
+	_, err = ResolveFetchTarget("versions.bzl", content, "GOLANGCI", "", "darwin", "arm64")
+	assert.Error(t, err, "ResolveFetchTarget() should reject an unpublished platform")
+}
+
+func TestRunner_Fetch(t *testing.T) {
+	archive := makeTarGz(t, map[string][]byte{
+		"golangci-lint-2.6.1-linux-amd64/golangci-lint": []byte("binary"),
+		"golangci-lint-2.6.1-linux-amd64/LICENSE":       []byte("license"),
+	})
+	url := "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz"
+
+	workspace := t.TempDir()
+	dest := t.TempDir()
+	tool := writeFetchVersions(t, workspace, map[string]string{"v2.6.1": sha256Hex(archive), "v2.6.0": "bbb"})
+
+	mock := NewMockGitHubClient()
+	mock.AddAsset(url, archive)
+	runner := NewRunner(Config{WorkspaceRoot: workspace}, mock)
+
+	path, err := runner.Fetch(context.Background(), tool, "latest", "linux", "amd64", dest)
+	require.NoError(t, err, "Fetch() should succeed")
+	assert.Equal(t, filepath.Join(dest, "golangci-lint", "v2.6.1", "linux_amd64", "golangci-lint"), path, "Fetch() should install into the per-version directory")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err, "Fetch() should extract the binary")
+	assert.Equal(t, "binary", string(content), "Fetch() should extract the archive's binary")
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.NotZero(t, info.Mode()&0100, "Fetch() should keep the binary executable")
+	assert.FileExists(t, filepath.Join(filepath.Dir(path), "LICENSE"), "Fetch() should extract the whole archive")
+
+	// A second fetch reuses the installation instead of downloading again
+	mock.DownloadError = assert.AnError
+	again, err := runner.Fetch(context.Background(), tool, "", "linux", "amd64", dest)
+	require.NoError(t, err, "Fetch() should reuse the installation")
+	assert.Equal(t, path, again, "Fetch() should return the installed binary")
+}
+
+func TestRunner_Fetch_ChecksumMismatch(t *testing.T) {
+	workspace := t.TempDir()
+	dest := t.TempDir()
+	tool := writeFetchVersions(t, workspace, map[string]string{"v2.6.1": sha256Hex([]byte("expected")), "v2.6.0": "bbb"})
+
+	mock := NewMockGitHubClient()
+	mock.AddAsset("https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz", []byte("tampered"))
+
+	_, err := NewRunner(Config{WorkspaceRoot: workspace}, mock).Fetch(context.Background(), tool, "", "linux", "amd64", dest)
+	assert.ErrorContains(t, err, "checksum mismatch", "Fetch() should reject an archive not matching the pinned hash")
+	assert.NoDirExists(t, filepath.Join(dest, "golangci-lint", "v2.6.1"), "Fetch() should not install an unverified archive")
+}
+
+func TestExtractArchive(t *testing.T) {
+	dest := t.TempDir()
+	archive := makeZip(t, map[string][]byte{"tool-1.0.0/bin/tool.exe": []byte("exe"), "tool-1.0.0/README": []byte("readme")})
+	require.NoError(t, extractArchive("tool-1.0.0.zip", archive, dest, "tool-1.0.0"), "extractArchive() should extract a zip")
+	assert.FileExists(t, filepath.Join(dest, "bin", "tool.exe"), "extractArchive() should strip the prefix")
+	assert.FileExists(t, filepath.Join(dest, "README"), "extractArchive() should extract every file")
+
+	for _, name := range []string{"../evil", "tool-1.0.0/../../evil", "/etc/evil"} {
+		archive := makeTarGz(t, map[string][]byte{name: []byte("x")})
+		err := extractArchive("tool.tar.gz", archive, t.TempDir(), "tool-1.0.0")
+		assert.Error(t, err, "extractArchive() should reject %q", name)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bazelbuild/buildtools/build"
+)
+
+// FormatStarlarkFile formats content the way buildifier does, so generated files pass
+// `buildifier --mode=check`. The file name selects the dialect (BUILD, MODULE.bazel or .bzl).
+func FormatStarlarkFile(filename string, content []byte) ([]byte, error) {
+	f, err := build.Parse(filepath.Base(filename), content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Starlark: %w", err)
+	}
+	return build.Format(f), nil
+}
+
+// writeStarlarkFileAtomic formats content and writes it to outputPath atomically,
+// reporting whether the file changed.
+func writeStarlarkFileAtomic(outputPath string, content []byte) (bool, error) {
+	formatted, err := FormatStarlarkFile(outputPath, content)
+	if err != nil {
+		return false, err
+	}
+	return writeFileAtomic(outputPath, formatted)
+}
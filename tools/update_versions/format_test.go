@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatStarlarkFile(t *testing.T) {
+	t.Run("bzl file", func(t *testing.T) {
+		formatted, err := FormatStarlarkFile("versions.bzl", []byte("X = {\n  \"b\": 1, \"a\" : [1,2,],\n}\n"))
+		require.NoError(t, err, "FormatStarlarkFile() should succeed")
+		assert.Equal(t, "X = {\n    \"b\": 1,\n    \"a\": [1, 2],\n}\n", string(formatted),
+			"FormatStarlarkFile() should apply buildifier formatting")
+	})
+
+	t.Run("BUILD file sorts attributes", func(t *testing.T) {
+		formatted, err := FormatStarlarkFile("/tmp/pkg/BUILD.bazel", []byte(`filegroup(srcs=["b","a"], name="x")`))
+		require.NoError(t, err, "FormatStarlarkFile() should succeed")
+		assert.Equal(t, "filegroup(\n    name = \"x\",\n    srcs = [\n        \"a\",\n        \"b\",\n    ],\n)\n", string(formatted),
+			"FormatStarlarkFile() should use the BUILD dialect for BUILD files")
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		_, err := FormatStarlarkFile("versions.bzl", []byte("X = {"))
+		assert.Error(t, err, "FormatStarlarkFile() should reject invalid Starlark")
+	})
+}
+
+func TestGenerateStarlarkFile_Canonical(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "versions.bzl")
+	data := &TemplateData{
+		GeneratedAt:    "2025-01-01T00:00:00Z",
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{{
+			Tag:           "v2.6.1",
+			GoVersion:     "1.25.3",
+			ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "abc", "arm64": "def"}},
+		}},
+	}
+
+	_, err := GenerateStarlarkFile(data, outputFile)
+	require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output file")
+
+	formatted, err := FormatStarlarkFile(outputFile, content)
+	require.NoError(t, err, "FormatStarlarkFile() should succeed")
+	assert.Equal(t, string(formatted), string(content), "GenerateStarlarkFile() output should already be buildifier-formatted")
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// GiteaClient fetches releases from a Gitea (or Forgejo) instance through the REST API v1.
+type GiteaClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// giteaRelease is the subset of the Gitea release API response we use.
+type giteaRelease struct {
+	TagName string       `json:"tag_name"`
+	Draft   bool         `json:"draft"`
+	Assets  []giteaAsset `json:"assets"`
+}
+
+// giteaAsset is a release attachment. Gitea does not report content type.
+type giteaAsset struct {
+	Name               string `json:"name"`
+	Size               int64  `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// NewGiteaClient creates a client for the Gitea instance at baseURL
+// (e.g. https://gitea.com). An empty token means unauthenticated access; a nil
+// httpClient means a client with the default HTTPConfig.
+func NewGiteaClient(httpClient *http.Client, baseURL, token string) *GiteaClient {
+	return &GiteaClient{
+		baseURL:    trimBaseURL(baseURL),
+		token:      token,
+		httpClient: clientOrDefault(httpClient),
+	}
+}
+
+// GetLatestReleases fetches the last N releases from the given "owner/name" repository.
+// Draft releases are skipped.
+func (c *GiteaClient) GetLatestReleases(ctx context.Context, repo string, count int) ([]Release, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository %q: expected owner/name", repo)
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/releases?limit=%d",
+		c.baseURL, url.PathEscape(owner), url.PathEscape(name), count)
+
+	body, err := httpGet(ctx, c.httpClient, endpoint, c.headers(), maxListingSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var gtReleases []giteaRelease
+	if err := json.Unmarshal(body, &gtReleases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	releases := make([]Release, 0, len(gtReleases))
+	for _, r := range gtReleases {
+		if r.Draft {
+			continue
+		}
+		var assets []Asset
+		for _, a := range r.Assets {
+			assets = append(assets, Asset{Name: a.Name, Size: a.Size, URL: a.BrowserDownloadURL})
+		}
+
+		releases = append(releases, Release{TagName: r.TagName, Assets: assets})
+	}
+
+	return releases, nil
+}
+
+// AssetURL returns the browser download URL of a release asset.
+func (c *GiteaClient) AssetURL(repo, tag, name string) string {
+	return fmt.Sprintf("%s/%s/releases/download/%s/%s", c.baseURL, repo, tag, name)
+}
+
+// DownloadAsset downloads an asset from a URL and returns the contents. The token is
+// only sent to the instance itself; release links may point at other hosts.
+func (c *GiteaClient) DownloadAsset(ctx context.Context, url string) ([]byte, error) {
+	var headers map[string]string
+	if sameOrigin(url, c.baseURL) {
+		headers = c.headers()
+	}
+	return httpGet(ctx, c.httpClient, url, headers, assetSizeLimit(url))
+}
+
+// headers returns the authentication headers for API requests.
+func (c *GiteaClient) headers() map[string]string {
+	if c.token == "" {
+		return nil
+	}
+	return map[string]string{"Authorization": "token " + c.token}
+}
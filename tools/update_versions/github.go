@@ -3,42 +3,72 @@ package main
 import (
 	"context"
 	"fmt"
-	"io"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v62/github"
 )
 
-// Release represents a GitHub release with basic information.
-type Release struct {
-	TagName string
+// GitHubClient wraps the GitHub API client for fetching tool releases from
+// github.com or a GitHub Enterprise Server instance.
+type GitHubClient struct {
+	client     *github.Client
+	httpClient *http.Client
+	webURL     string
 }
 
-// GitHubAPI defines the interface for interacting with GitHub.
-type GitHubAPI interface {
-	GetLatestReleases(ctx context.Context, count int) ([]Release, error)
-	DownloadAsset(ctx context.Context, url string) ([]byte, error)
+// NewGitHubClient creates a new GitHub API client for github.com making requests through
+// httpClient; nil means a client with the default HTTPConfig.
+func NewGitHubClient(httpClient *http.Client) *GitHubClient {
+	httpClient = clientOrDefault(httpClient)
+	return &GitHubClient{
+		client:     github.NewClient(httpClient),
+		httpClient: httpClient,
+		webURL:     "https://github.com",
+	}
 }
 
-// GitHubClient wraps the GitHub API client for fetching golangci-lint releases.
-type GitHubClient struct {
-	client *github.Client
+// NewGitHubEnterpriseClient creates a GitHub API client for a GitHub Enterprise
+// Server instance at baseURL (e.g. https://github.example.com).
+func NewGitHubEnterpriseClient(httpClient *http.Client, baseURL string) (*GitHubClient, error) {
+	httpClient = clientOrDefault(httpClient)
+	webURL := trimBaseURL(baseURL)
+	client, err := github.NewClient(httpClient).WithEnterpriseURLs(webURL+"/api/v3/", webURL+"/api/uploads/")
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub Enterprise URL %q: %w", baseURL, err)
+	}
+
+	return &GitHubClient{
+		client:     client,
+		httpClient: httpClient,
+		webURL:     webURL,
+	}, nil
 }
 
-// NewGitHubClient creates a new GitHub API client.
-func NewGitHubClient() *GitHubClient {
+// WithToken returns the client authenticated with token, or unchanged if token is empty.
+func (c *GitHubClient) WithToken(token string) *GitHubClient {
+	if token == "" {
+		return c
+	}
 	return &GitHubClient{
-		client: github.NewClient(nil),
+		client:     c.client.WithAuthToken(token),
+		httpClient: c.httpClient,
+		webURL:     c.webURL,
 	}
 }
 
-// GetLatestReleases fetches the last N releases from the golangci-lint repository.
-func (c *GitHubClient) GetLatestReleases(ctx context.Context, count int) ([]Release, error) {
+// GetLatestReleases fetches the last N releases from the given "owner/name" repository.
+func (c *GitHubClient) GetLatestReleases(ctx context.Context, repo string, count int) ([]Release, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid repository %q: expected owner/name", repo)
+	}
+
 	opts := &github.ListOptions{
 		PerPage: count,
 	}
 
-	ghReleases, _, err := c.client.Repositories.ListReleases(ctx, "golangci", "golangci-lint", opts)
+	ghReleases, _, err := c.client.Repositories.ListReleases(ctx, owner, name, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list releases: %w", err)
 	}
@@ -46,35 +76,31 @@ func (c *GitHubClient) GetLatestReleases(ctx context.Context, count int) ([]Rele
 	// Convert to our Release type
 	releases := make([]Release, 0, len(ghReleases))
 	for _, r := range ghReleases {
+		var assets []Asset
+		for _, a := range r.Assets {
+			assets = append(assets, Asset{
+				Name:        a.GetName(),
+				Size:        int64(a.GetSize()),
+				URL:         a.GetBrowserDownloadURL(),
+				ContentType: a.GetContentType(),
+			})
+		}
+
 		releases = append(releases, Release{
 			TagName: r.GetTagName(),
+			Assets:  assets,
 		})
 	}
 
 	return releases, nil
 }
 
+// AssetURL returns the browser download URL of a release asset.
+func (c *GitHubClient) AssetURL(repo, tag, name string) string {
+	return fmt.Sprintf("%s/%s/releases/download/%s/%s", c.webURL, repo, tag, name)
+}
+
 // DownloadAsset downloads an asset from a URL and returns the contents.
 func (c *GitHubClient) DownloadAsset(ctx context.Context, url string) ([]byte, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to download asset: %w", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	return body, nil
+	return httpGet(ctx, c.httpClient, url, nil, assetSizeLimit(url))
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// GitLabClient fetches releases from a GitLab instance through the REST API v4.
+type GitLabClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// gitlabRelease is the subset of the GitLab release API response we use.
+type gitlabRelease struct {
+	TagName         string `json:"tag_name"`
+	UpcomingRelease bool   `json:"upcoming_release"`
+	Assets          struct {
+		Links []gitlabAssetLink `json:"links"`
+	} `json:"assets"`
+}
+
+// gitlabAssetLink is a release asset link. GitLab does not report size or content type.
+type gitlabAssetLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+// NewGitLabClient creates a client for the GitLab instance at baseURL
+// (e.g. https://gitlab.com). An empty token means unauthenticated access; a nil
+// httpClient means a client with the default HTTPConfig.
+func NewGitLabClient(httpClient *http.Client, baseURL, token string) *GitLabClient {
+	return &GitLabClient{
+		baseURL:    trimBaseURL(baseURL),
+		token:      token,
+		httpClient: clientOrDefault(httpClient),
+	}
+}
+
+// GetLatestReleases fetches the last N releases of a project given by its full path
+// (e.g. group/subgroup/project). Upcoming releases are skipped.
+func (c *GitLabClient) GetLatestReleases(ctx context.Context, repo string, count int) ([]Release, error) {
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/releases?per_page=%d&order_by=released_at&sort=desc",
+		c.baseURL, url.PathEscape(repo), count)
+
+	body, err := httpGet(ctx, c.httpClient, endpoint, c.headers(), maxListingSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list releases: %w", err)
+	}
+
+	var glReleases []gitlabRelease
+	if err := json.Unmarshal(body, &glReleases); err != nil {
+		return nil, fmt.Errorf("failed to decode releases: %w", err)
+	}
+
+	releases := make([]Release, 0, len(glReleases))
+	for _, r := range glReleases {
+		if r.UpcomingRelease {
+			continue
+		}
+		var assets []Asset
+		for _, l := range r.Assets.Links {
+			url := l.DirectAssetURL
+			if url == "" {
+				url = l.URL
+			}
+			assets = append(assets, Asset{Name: l.Name, URL: url})
+		}
+
+		releases = append(releases, Release{TagName: r.TagName, Assets: assets})
+	}
+
+	return releases, nil
+}
+
+// AssetURL returns the permanent release link for an asset. GitLab resolves it through
+// the asset's direct_asset_path, which projects conventionally set to the file name.
+func (c *GitLabClient) AssetURL(repo, tag, name string) string {
+	return fmt.Sprintf("%s/%s/-/releases/%s/downloads/%s", c.baseURL, repo, url.PathEscape(tag), name)
+}
+
+// DownloadAsset downloads an asset from a URL and returns the contents. The token is
+// only sent to the instance itself; release links may point at other hosts.
+func (c *GitLabClient) DownloadAsset(ctx context.Context, url string) ([]byte, error) {
+	var headers map[string]string
+	if sameOrigin(url, c.baseURL) {
+		headers = c.headers()
+	}
+	return httpGet(ctx, c.httpClient, url, headers, assetSizeLimit(url))
+}
+
+// headers returns the authentication headers for API requests.
+func (c *GitLabClient) headers() map[string]string {
+	if c.token == "" {
+		return nil
+	}
+	return map[string]string{"PRIVATE-TOKEN": c.token}
+}
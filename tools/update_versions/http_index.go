@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// HTTPIndexSource reads releases from a static JSON index document, for mirrors
+// and artifact stores without a release API. The document lists releases newest first,
+// each with an optional checksum file URL and asset list:
+//
+//	{"releases": [{"tag": "v2.6.1", "checksum_url": "v2.6.1/checksums.txt",
+//	  "assets": [{"name": "tool-linux-amd64.tar.gz", "size": 123, "url": "v2.6.1/tool-linux-amd64.tar.gz"}]}]}
+//
+// Relative URLs are resolved against the index URL.
+type HTTPIndexSource struct {
+	indexURL   string
+	httpClient *http.Client
+}
+
+// httpIndex is the JSON document served at the index URL.
+type httpIndex struct {
+	Releases []httpIndexRelease `json:"releases"`
+}
+
+// httpIndexRelease is a single release entry in the index.
+type httpIndexRelease struct {
+	Tag         string           `json:"tag"`
+	ChecksumURL string           `json:"checksum_url"`
+	Assets      []httpIndexAsset `json:"assets"`
+}
+
+// httpIndexAsset is a single asset entry of a release in the index.
+type httpIndexAsset struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+}
+
+// NewHTTPIndexSource creates a source reading the index document at indexURL through
+// httpClient; nil means a client with the default HTTPConfig.
+func NewHTTPIndexSource(httpClient *http.Client, indexURL string) *HTTPIndexSource {
+	return &HTTPIndexSource{
+		indexURL:   indexURL,
+		httpClient: clientOrDefault(httpClient),
+	}
+}
+
+// GetLatestReleases fetches the index and returns its first N releases.
+// The repo argument is ignored; the index URL identifies the tool.
+func (s *HTTPIndexSource) GetLatestReleases(ctx context.Context, _ string, count int) ([]Release, error) {
+	body, err := httpGet(ctx, s.httpClient, s.indexURL, nil, maxListingSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release index: %w", err)
+	}
+
+	var index httpIndex
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("failed to decode release index: %w", err)
+	}
+
+	if count > len(index.Releases) {
+		count = len(index.Releases)
+	}
+
+	releases := make([]Release, 0, count)
+	for _, r := range index.Releases[:count] {
+		release := Release{TagName: r.Tag}
+		if r.ChecksumURL != "" {
+			release.ChecksumURL = s.resolve(r.ChecksumURL)
+		}
+		for _, a := range r.Assets {
+			release.Assets = append(release.Assets, Asset{
+				Name:        a.Name,
+				Size:        a.Size,
+				URL:         s.resolve(a.URL),
+				ContentType: a.ContentType,
+			})
+		}
+		releases = append(releases, release)
+	}
+
+	return releases, nil
+}
+
+// AssetURL returns <tag>/<name> resolved against the index URL.
+func (s *HTTPIndexSource) AssetURL(_, tag, name string) string {
+	return s.resolve(url.PathEscape(tag) + "/" + url.PathEscape(name))
+}
+
+// DownloadAsset downloads an asset from a URL and returns the contents.
+func (s *HTTPIndexSource) DownloadAsset(ctx context.Context, url string) ([]byte, error) {
+	return httpGet(ctx, s.httpClient, url, nil, assetSizeLimit(url))
+}
+
+// resolve resolves ref against the index URL, returning ref unchanged if either fails to parse.
+func (s *HTTPIndexSource) resolve(ref string) string {
+	base, err := url.Parse(s.indexURL)
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
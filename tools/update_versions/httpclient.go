@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"slices"
+	"time"
+)
+
+// buildVersion is the updater's version, reported in the User-Agent header. Release builds
+// set it with -ldflags "-X main.buildVersion=<version>"; otherwise the module version from
+// the build info is used.
+var buildVersion = ""
+
+// toolVersion returns buildVersion, the module version the binary was built from, or "devel".
+func toolVersion() string {
+	if buildVersion != "" {
+		return buildVersion
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "devel"
+}
+
+// HTTPConfig configures the HTTP client shared by the release sources, asset downloads
+// and the pull request client.
+type HTTPConfig struct {
+	// ProxyURL is the proxy for every request; empty means HTTPS_PROXY, HTTP_PROXY and
+	// NO_PROXY from the environment.
+	ProxyURL string
+	// CABundle is a PEM file of CA certificates trusted in addition to the system roots,
+	// e.g. the certificate of a TLS-inspecting proxy.
+	CABundle string
+	// RequestTimeout bounds each request, including reading its body; zero means no limit.
+	RequestTimeout time.Duration
+	// UserAgent is sent with every request; empty means update_versions/<version>.
+	UserAgent string
+	// RedirectHosts are hosts redirects may lead to in addition to the requested host and
+	// defaultRedirectHosts.
+	RedirectHosts []string
+}
+
+// NewHTTPClient creates a client from cfg for the release sources and the pull request
+// client. Release listings made through it are served through the release snapshot
+// attached to the request context, if any.
+func NewHTTPClient(cfg HTTPConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxy, err := url.Parse(cfg.ProxyURL)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", cfg.ProxyURL)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme %q (want http, https or socks5)", proxy.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if cfg.CABundle != "" {
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s contains no PEM certificates", cfg.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return newHTTPClient(transport, cfg), nil
+}
+
+// newHTTPClient wraps transport with the user agent, release snapshot and redirect policy
+// of cfg.
+func newHTTPClient(transport http.RoundTripper, cfg HTTPConfig) *http.Client {
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = "update_versions/" + toolVersion()
+	}
+
+	return &http.Client{
+		Transport:     &snapshotTransport{base: &userAgentTransport{base: transport, userAgent: userAgent}},
+		CheckRedirect: redirectPolicy(append(slices.Clone(defaultRedirectHosts), cfg.RedirectHosts...)),
+		Timeout:       cfg.RequestTimeout,
+	}
+}
+
+// clientOrDefault returns client, or a client with the default HTTPConfig if it is nil.
+func clientOrDefault(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return newHTTPClient(http.DefaultTransport, HTTPConfig{})
+}
+
+// userAgentTransport sets the User-Agent header of every request.
+type userAgentTransport struct {
+	base      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.base.RoundTrip(req)
+}
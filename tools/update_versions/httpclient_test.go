@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewHTTPClient_UserAgent(t *testing.T) {
+	var headers http.Header
+	server := newReleaseServer(t, map[string]string{"/asset": "data"}, &headers)
+
+	client, err := NewHTTPClient(HTTPConfig{})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	_, err = httpGet(context.Background(), client, server.URL+"/asset", nil, maxChecksumSize)
+	require.NoError(t, err, "httpGet() should succeed")
+	assert.Equal(t, "update_versions/"+toolVersion(), headers.Get("User-Agent"), "requests should carry the tool version")
+
+	client, err = NewHTTPClient(HTTPConfig{UserAgent: "custom/1.0"})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	_, err = httpGet(context.Background(), client, server.URL+"/asset", nil, maxChecksumSize)
+	require.NoError(t, err, "httpGet() should succeed")
+	assert.Equal(t, "custom/1.0", headers.Get("User-Agent"), "a configured user agent should be used")
+}
+
+func TestRunner_UsesConfiguredHTTPClient(t *testing.T) {
+	var headers http.Header
+	server := newReleaseServer(t, map[string]string{
+		"/api/v1/repos/mirror/golangci-lint/releases":                                      `[{"tag_name": "v2.6.1"}]`,
+		"/mirror/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt": "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n",
+	}, &headers)
+
+	tempDir := t.TempDir()
+	tool := Tool{
+		Name:       "golangci-lint",
+		Repo:       "mirror/golangci-lint",
+		OutputFile: "versions.bzl",
+		Source:     &SourceConfig{Type: SourceGitea, URL: server.URL},
+	}
+	require.NoError(t, tool.Validate(), "Tool.Validate() should succeed")
+
+	client, err := NewHTTPClient(HTTPConfig{UserAgent: "configured/1.0"})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{tool},
+		HTTPClient:    client,
+	}
+	require.NoError(t, NewRunner(config, NewMockGitHubClient()).Run(context.Background()), "Runner.Run() should succeed")
+	assert.Equal(t, "configured/1.0", headers.Get("User-Agent"), "tool sources should use the configured client")
+}
+
+func TestNewHTTPClient_CABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("secure"))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewHTTPClient(HTTPConfig{})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	_, err = httpGet(context.Background(), client, server.URL, nil, maxChecksumSize)
+	assert.Error(t, err, "an untrusted certificate should be rejected")
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(bundle, cert, 0644))
+	client, err = NewHTTPClient(HTTPConfig{CABundle: bundle})
+	require.NoError(t, err, "NewHTTPClient() should accept a CA bundle")
+	body, err := httpGet(context.Background(), client, server.URL, nil, maxChecksumSize)
+	require.NoError(t, err, "a certificate from the CA bundle should be trusted")
+	assert.Equal(t, "secure", string(body), "httpGet() should return the body")
+
+	require.NoError(t, os.WriteFile(bundle, []byte("not a certificate"), 0644))
+	_, err = NewHTTPClient(HTTPConfig{CABundle: bundle})
+	assert.Error(t, err, "NewHTTPClient() should reject a bundle without certificates")
+	_, err = NewHTTPClient(HTTPConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.Error(t, err, "NewHTTPClient() should reject a missing bundle")
+}
+
+func TestNewHTTPClient_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		_, _ = w.Write([]byte("via proxy"))
+	}))
+	t.Cleanup(proxy.Close)
+
+	client, err := NewHTTPClient(HTTPConfig{ProxyURL: proxy.URL})
+	require.NoError(t, err, "NewHTTPClient() should accept a proxy URL")
+	body, err := httpGet(context.Background(), client, "http://releases.example.com/asset", nil, maxChecksumSize)
+	require.NoError(t, err, "httpGet() should succeed through the proxy")
+	assert.Equal(t, "via proxy", string(body), "the response should come from the proxy")
+	assert.Equal(t, "http://releases.example.com/asset", proxied, "the proxy should receive the request")
+
+	for _, invalid := range []string{"ftp://proxy.example.com", "://", "proxy.example.com"} {
+		_, err := NewHTTPClient(HTTPConfig{ProxyURL: invalid})
+		assert.Error(t, err, "NewHTTPClient() should reject proxy %q", invalid)
+	}
+}
+
+func TestNewHTTPClient_RequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	client, err := NewHTTPClient(HTTPConfig{RequestTimeout: 50 * time.Millisecond})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	_, err = httpGet(context.Background(), client, server.URL, nil, maxChecksumSize)
+	assert.Error(t, err, "a request exceeding the timeout should fail")
+}
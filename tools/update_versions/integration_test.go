@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -102,6 +103,65 @@ func TestRunner_Run_SuccessfulWorkflowWithMultipleVersions(t *testing.T) {
 	}
 }
 
+func TestRunner_Run_KeepsNewestOfEachMajor(t *testing.T) {
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "versions.bzl")
+
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		OutputFile:    outputFile,
+		WorkspaceRoot: tempDir,
+	}
+
+	// A v1 config must still find a v1 release when the newest releases are all v2
+	mock := NewMockGitHubClient()
+	for _, tag := range []string{"v2.6.1", "v2.6.0", "v1.64.8", "v1.64.7"} {
+		mock.AddRelease(tag)
+		version := tag[1:]
+		url := fmt.Sprintf("https://github.com/golangci/golangci-lint/releases/download/%s/golangci-lint-%s-checksums.txt", tag, version)
+		mock.AddAsset(url, []byte(fmt.Sprintf(
+			"aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-%s-linux-amd64.tar.gz\n", version,
+		)))
+	}
+
+	require.NoError(t, NewRunner(config, mock).Run(context.Background()), "Runner.Run() should succeed")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output file")
+	contentStr := string(content)
+	assert.Contains(t, contentStr, "DEFAULT_VERSIONS = {\n    \"v1\": \"v1.64.8\",\n    \"v2\": \"v2.6.1\",\n}",
+		"Runner.Run() should keep a default for every major line")
+	assert.Contains(t, contentStr, `DEFAULT_VERSION = "v2.6.1"`, "the newest release should stay the default")
+	assert.NotContains(t, contentStr, "v2.6.0", "Runner.Run() should only add the newest release of older major lines")
+	assert.NotContains(t, contentStr, "v1.64.7", "Runner.Run() should only add the newest release of older major lines")
+}
+
+func TestSelectReleases(t *testing.T) {
+	releases := []Release{
+		{TagName: "v3.0.0-rc.1"},
+		{TagName: "v2.6.1"},
+		{TagName: "v2.6.0"},
+		{TagName: "v1.64.8"},
+		{TagName: "../../v0.9.0"},
+		{TagName: "v1.64.7"},
+		{TagName: "v0.9.0"},
+	}
+
+	var tags, olderMajors []string
+	for _, release := range selectReleases(releases, 2) {
+		tags = append(tags, release.TagName)
+		if release.OlderMajor {
+			olderMajors = append(olderMajors, release.TagName)
+		}
+	}
+	assert.Equal(t, []string{"v3.0.0-rc.1", "v2.6.1", "v1.64.8", "v0.9.0"}, tags,
+		"selectReleases() should keep the newest releases and the newest stable release of each other major line")
+	assert.Equal(t, []string{"v1.64.8", "v0.9.0"}, olderMajors, "selectReleases() should mark the releases kept beyond the window")
+
+	assert.Len(t, selectReleases(releases[:2], 5), 2, "selectReleases() should handle fewer releases than requested")
+}
+
 func TestRunner_Run_CacheHit(t *testing.T) {
 	tempDir := t.TempDir()
 	cacheDir := filepath.Join(tempDir, "cache")
@@ -249,34 +309,11 @@ func TestRunner_Run_SkipsInvalidChecksums(t *testing.T) {
 	assert.Contains(t, contentStr, "v2.6.0", "Runner.Run() output should contain v2.6.0")
 }
 
-func TestRunner_ResolveAbsolutePaths(t *testing.T) {
-	t.Run("converts relative paths", func(t *testing.T) {
-		config := Config{
-			CacheDir:      "cache",
-			OutputFile:    "output.bzl",
-			WorkspaceRoot: "/workspace",
-		}
-
-		runner := NewRunner(config, nil)
-		absCacheDir, absOutputFile := runner.resolveAbsolutePaths()
-
-		assert.Equal(t, "/workspace/cache", absCacheDir, "resolveAbsolutePaths() should convert relative cache path")
-		assert.Equal(t, "/workspace/output.bzl", absOutputFile, "resolveAbsolutePaths() should convert relative output path")
-	})
+func TestRunner_ResolvePath(t *testing.T) {
+	runner := NewRunner(Config{WorkspaceRoot: "/workspace"}, nil)
 
-	t.Run("preserves absolute paths", func(t *testing.T) {
-		config := Config{
-			CacheDir:      "/absolute/cache",
-			OutputFile:    "/absolute/output.bzl",
-			WorkspaceRoot: "/workspace",
-		}
-
-		runner := NewRunner(config, nil)
-		absCacheDir, absOutputFile := runner.resolveAbsolutePaths()
-
-		assert.Equal(t, "/absolute/cache", absCacheDir, "resolveAbsolutePaths() should preserve absolute cache path")
-		assert.Equal(t, "/absolute/output.bzl", absOutputFile, "resolveAbsolutePaths() should preserve absolute output path")
-	})
+	assert.Equal(t, "/workspace/cache", runner.resolvePath("cache"), "resolvePath() should convert relative paths")
+	assert.Equal(t, "/absolute/output.bzl", runner.resolvePath("/absolute/output.bzl"), "resolvePath() should preserve absolute paths")
 }
 
 func TestRunner_ProcessReleases(t *testing.T) {
@@ -295,7 +332,9 @@ func TestRunner_ProcessReleases(t *testing.T) {
 		releases := []Release{{TagName: "v2.6.1"}}
 		ctx := context.Background()
 
-		versions := runner.processReleases(ctx, releases, tempDir)
+		index, err := LoadCacheIndex(tempDir)
+		require.NoError(t, err, "LoadCacheIndex() should succeed")
+		versions := runner.processReleases(ctx, DefaultTool(), mock, releases, tempDir, index)
 
 		require.Len(t, versions, 1, "processReleases() should return 1 version")
 		assert.Equal(t, "v2.6.1", versions[0].Tag, "processReleases() should have correct tag")
@@ -311,8 +350,137 @@ func TestRunner_ProcessReleases(t *testing.T) {
 		releases := []Release{{TagName: ""}}
 		ctx := context.Background()
 
-		versions := runner.processReleases(ctx, releases, tempDir)
+		index, err := LoadCacheIndex(tempDir)
+		require.NoError(t, err, "LoadCacheIndex() should succeed")
+		versions := runner.processReleases(ctx, DefaultTool(), mock, releases, tempDir, index)
 
 		assert.Empty(t, versions, "processReleases() should skip releases with empty tags")
 	})
 }
+
+func TestRunner_Run_MultipleTools(t *testing.T) {
+	tempDir := t.TempDir()
+	cacheDir := filepath.Join(tempDir, "cache")
+
+	golangci := DefaultTool()
+	golangci.OutputFile = "golangci_lint/versions.bzl"
+
+	gofumpt := Tool{
+		Name:         "gofumpt",
+		Repo:         "mvdan/gofumpt",
+		AssetPattern: "{name}_v{version}_{os}_{arch}.{ext}",
+		ChecksumFile: "sha256sums.txt",
+		OutputFile:   "gofumpt/versions.bzl",
+	}
+	require.NoError(t, gofumpt.Validate(), "Tool.Validate() should succeed")
+
+	config := Config{
+		Count:         1,
+		CacheDir:      cacheDir,
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{golangci, gofumpt},
+	}
+
+	mock := NewMockGitHubClient()
+	mock.AddRepoRelease("golangci/golangci-lint", "v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"),
+	)
+	mock.AddRepoRelease("mvdan/gofumpt", "v0.7.0")
+	mock.AddAsset(
+		"https://github.com/mvdan/gofumpt/releases/download/v0.7.0/sha256sums.txt",
+		[]byte("bbb2222222222222222222222222222222222222222222222222222222222222  gofumpt_v0.7.0_darwin_arm64.tar.gz\n"),
+	)
+
+	runner := NewRunner(config, mock)
+	err := runner.Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed")
+
+	// Each tool gets its own cache directory
+	_, err = os.Stat(filepath.Join(cacheDir, "v2.6.1.txt"))
+	assert.NoError(t, err, "Runner.Run() should cache golangci-lint in the cache root")
+	_, err = os.Stat(filepath.Join(cacheDir, "gofumpt", "v0.7.0.txt"))
+	assert.NoError(t, err, "Runner.Run() should cache gofumpt in its own subdirectory")
+
+	golangciOut, err := os.ReadFile(filepath.Join(tempDir, "golangci_lint/versions.bzl"))
+	require.NoError(t, err, "Failed to read golangci-lint output")
+	assert.Contains(t, string(golangciOut), "GOLANGCI_VERSIONS = {", "golangci-lint output should use GOLANGCI prefix")
+	assert.Contains(t, string(golangciOut), "def get_golangci_version_info(", "golangci-lint output should keep accessor name")
+
+	gofumptOut, err := os.ReadFile(filepath.Join(tempDir, "gofumpt/versions.bzl"))
+	require.NoError(t, err, "Failed to read gofumpt output")
+	assert.Contains(t, string(gofumptOut), "GOFUMPT_VERSIONS = {", "gofumpt output should use GOFUMPT prefix")
+	assert.Contains(t, string(gofumptOut), "def get_gofumpt_version_info(", "gofumpt output should use derived accessor name")
+	assert.Contains(t, string(gofumptOut), "bbb2222222222222222222222222222222222222222222222222222222222222", "gofumpt output should contain its checksum")
+	assert.NotContains(t, string(gofumptOut), "aaa1111111111111111111111111111111111111111111111111111111111111", "gofumpt output should not contain golangci-lint checksums")
+}
+
+func TestRunner_Run_ToolFailureDoesNotStopOthers(t *testing.T) {
+	tempDir := t.TempDir()
+
+	buf := Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "buf/versions.bzl"}
+	require.NoError(t, buf.Validate(), "Tool.Validate() should succeed")
+
+	golangci := DefaultTool()
+	golangci.OutputFile = "golangci_lint/versions.bzl"
+
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{buf, golangci},
+	}
+
+	// No releases for buf; golangci-lint succeeds
+	mock := NewMockGitHubClient()
+	mock.RepoReleases["bufbuild/buf"] = nil
+	mock.AddRepoRelease("golangci/golangci-lint", "v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"),
+	)
+
+	err := NewRunner(config, mock).Run(context.Background())
+	require.Error(t, err, "Runner.Run() should report the failing tool")
+	assert.Contains(t, err.Error(), "buf:", "Error should name the failing tool")
+
+	_, err = os.Stat(filepath.Join(tempDir, "golangci_lint/versions.bzl"))
+	assert.NoError(t, err, "Runner.Run() should still generate output for other tools")
+}
+
+func TestRunner_Run_ReproducibleOutput(t *testing.T) {
+	tempDir := t.TempDir()
+	outputFile := filepath.Join(tempDir, "versions.bzl")
+
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		OutputFile:    outputFile,
+		WorkspaceRoot: tempDir,
+		OmitTimestamp: true,
+	}
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte("aee6e16af4dfa60dd3c4e39536edc905f28369fda3c138090db00c8233cfe450  golangci-lint-2.6.1-darwin-amd64.tar.gz\n"),
+	)
+
+	ctx := context.Background()
+	require.NoError(t, NewRunner(config, mock).Run(ctx), "first Runner.Run() should succeed")
+
+	first, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output file")
+	assert.NotContains(t, string(first), "Generated at", "Runner.Run() should omit the timestamp when configured")
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	require.NoError(t, os.Chtimes(outputFile, past, past), "Failed to set file times")
+
+	require.NoError(t, NewRunner(config, mock).Run(ctx), "second Runner.Run() should succeed")
+
+	info, err := os.Stat(outputFile)
+	require.NoError(t, err, "Failed to stat output file")
+	assert.True(t, info.ModTime().Equal(past), "Runner.Run() should not rewrite unchanged output")
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// NoAssertion is the SPDX value for a license that is unknown.
+const NoAssertion = "NOASSERTION"
+
+// licenseFileNames are the base names of license files looked for in release archives.
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING"}
+
+// licenseHeaderLines is how many non-blank lines at the top of a license file make up
+// its header.
+const licenseHeaderLines = 5
+
+// licenseTitles identifies a license by phrases of the title in its header. Only the
+// header is searched: license texts name other licenses in their body, e.g. the GPLv3
+// ends by recommending the GNU Lesser General Public License.
+var licenseTitles = []licenseMarker{
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"LGPL-3.0-only", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1-only", []string{"gnu lesser general public license", "version 2.1"}},
+	{"AGPL-3.0-only", []string{"gnu affero general public license", "version 3"}},
+	{"GPL-3.0-only", []string{"gnu general public license", "version 3"}},
+	{"MPL-2.0", []string{"mozilla public license version 2.0"}},
+}
+
+// licenseMarkers identifies licenses without a title, which start with a copyright line,
+// by phrases of their text, checked in order so that the BSD-3-Clause is not mistaken for
+// the BSD-2-Clause whose text it contains.
+var licenseMarkers = []licenseMarker{
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software"}},
+}
+
+// GPLv2 phrases, normalized as by matchLicense. The license text does not say whether a
+// program is under version 2 only or any later version; the notice shipped with it does.
+const (
+	// gpl2Title is the title and version line the GPLv2 starts with.
+	gpl2Title = "gnu general public license version 2, june 1991"
+	// gpl2AppendixStart and gpl2AppendixEnd delimit the "How to Apply These Terms"
+	// appendix, whose sample notice offers any later version on behalf of other programs.
+	gpl2AppendixStart = "how to apply these terms to your new programs"
+	gpl2AppendixEnd   = "instead of this license."
+	// gpl2LaterNotice is how a notice grants any later version.
+	gpl2LaterNotice = "or (at your option) any later version"
+)
+
+// gpl2OnlyNotices are phrases of a notice restricting a program to version 2.
+var gpl2OnlyNotices = []string{"version 2 only", "gpl-2.0-only", "gplv2 only"}
+
+// licenseMarker names the license whose text contains all of phrases.
+type licenseMarker struct {
+	id      string
+	phrases []string
+}
+
+// IdentifyLicense returns the SPDX identifier of a license text, or NoAssertion if it is
+// not one of the licenses in licenseTitles or licenseMarkers, or a GPLv2 text that does not
+// say whether later versions apply.
+func IdentifyLicense(text []byte) string {
+	var header []string
+	for _, line := range strings.Split(string(text), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		header = append(header, line)
+		if len(header) == licenseHeaderLines {
+			break
+		}
+	}
+
+	if id := matchLicense(licenseTitles, strings.Join(header, "\n")); id != NoAssertion {
+		return id
+	}
+	if id, ok := identifyGPL2(normalizeLicense(string(text))); ok {
+		return id
+	}
+	return matchLicense(licenseMarkers, string(text))
+}
+
+// identifyGPL2 reports whether normalized is a GPLv2 text and returns GPL-2.0-or-later or
+// GPL-2.0-only as its notice says, or NoAssertion if no notice outside the license's own
+// appendix settles which.
+func identifyGPL2(normalized string) (string, bool) {
+	if !strings.Contains(normalized, gpl2Title) {
+		return "", false
+	}
+
+	notice := normalized
+	if start := strings.Index(notice, gpl2AppendixStart); start >= 0 {
+		end := len(notice)
+		if n := strings.Index(notice[start:], gpl2AppendixEnd); n >= 0 {
+			end = start + n + len(gpl2AppendixEnd)
+		}
+		notice = notice[:start] + " " + notice[end:]
+	}
+
+	if strings.Contains(notice, gpl2LaterNotice) || strings.Contains(notice, "gpl-2.0-or-later") {
+		return "GPL-2.0-or-later", true
+	}
+	for _, phrase := range gpl2OnlyNotices {
+		if strings.Contains(notice, phrase) {
+			return "GPL-2.0-only", true
+		}
+	}
+	return NoAssertion, true
+}
+
+// normalizeLicense lowercases text and collapses its white space.
+func normalizeLicense(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// matchLicense returns the first of markers whose phrases all occur in text, ignoring case
+// and differences in white space, or NoAssertion.
+func matchLicense(markers []licenseMarker, text string) string {
+	normalized := normalizeLicense(text)
+	for _, m := range markers {
+		matched := true
+		for _, phrase := range m.phrases {
+			if !strings.Contains(normalized, phrase) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return m.id
+		}
+	}
+	return NoAssertion
+}
+
+// ReadLicense returns the SPDX identifier of the license file inside a release archive,
+// or NoAssertion if the archive has none or its text is not recognized.
+func ReadLicense(archiveName string, archive []byte) (string, error) {
+	content, name, err := readArchiveFile(archiveName, archive, baseNameIs(licenseFileNames...))
+	if errors.Is(err, errNotInArchive) {
+		return NoAssertion, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract license: %w", err)
+	}
+
+	id := IdentifyLicense(content)
+	if id == NoAssertion {
+		log.Printf("  Warning: license in %s could not be identified", name)
+	}
+	return id, nil
+}
+
+// loadLicense returns the license of a release's linux/amd64 archive, reading it from
+// licenseFile if cached, otherwise downloading and verifying the archive.
+func (r *Runner) loadLicense(ctx context.Context, tool Tool, source ReleaseSource, release Release, checksums map[Platform]string, licenseFile string) (string, error) {
+	if data, err := os.ReadFile(licenseFile); err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	name, archive, err := downloadVerifiedArchive(ctx, tool, source, release, checksums)
+	if err != nil {
+		return "", err
+	}
+
+	license, err := ReadLicense(name, archive)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := writeFileAtomic(licenseFile, []byte(license+"\n")); err != nil {
+		log.Printf("  Warning: failed to cache license: %v", err)
+		// Continue anyway - we have the license
+	}
+
+	return license, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdentifyLicense(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"GPL-2.0 without notice", "                    GNU GENERAL PUBLIC LICENSE\n                       Version 2, June 1991\n", NoAssertion},
+		{"GPL-2.0 title without version line", "GNU GENERAL PUBLIC LICENSE\nVersion 2\n", NoAssertion},
+		{"Apache-2.0", "                                 Apache License\n                           Version 2.0, January 2004", "Apache-2.0"},
+		{"MIT", "MIT License\n\nPermission is hereby granted, free of charge, to any person", "MIT"},
+		{"BSD-3-Clause", "Redistribution and use in source and binary forms, with or without\nmodification... Neither the name of", "BSD-3-Clause"},
+		{"BSD-2-Clause", "Redistribution and use in source and binary forms, with or without modification", "BSD-2-Clause"},
+		{"unknown", "All rights reserved.", NoAssertion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IdentifyLicense([]byte(tt.text)), "IdentifyLicense() should identify %s", tt.name)
+		})
+	}
+}
+
+func TestIdentifyLicense_GPL2(t *testing.T) {
+	// The license text ends with an appendix whose sample notice offers any later version
+	license := `                    GNU GENERAL PUBLIC LICENSE
+                       Version 2, June 1991
+
+ Copyright (C) 1989, 1991 Free Software Foundation, Inc.
+
+  9. ... If the Program specifies a version number of this License which applies to it
+and "any later version", you have the option of following the terms and conditions
+either of that version or of any later version ...
+
+                     END OF TERMS AND CONDITIONS
+
+            How to Apply These Terms to Your New Programs
+
+    This program is free software; you can redistribute it and/or modify
+    it under the terms of the GNU General Public License as published by
+    the Free Software Foundation; either version 2 of the License, or
+    (at your option) any later version.
+
+library.  If this is what you want to do, use the GNU Lesser General
+Public License instead of this License.
+`
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"license only", license, NoAssertion},
+		{"or-later notice", `This program is free software; you can redistribute it and/or modify it
+under the terms of the GNU General Public License as published by the Free Software
+Foundation; either version 2 of the License, or (at your option) any later version.
+
+` + license, "GPL-2.0-or-later"},
+		{"only notice", "This program is licensed under the GNU General Public License version 2 only.\n\n" + license, "GPL-2.0-only"},
+		{"notice after the license", license + "\nSPDX-License-Identifier: GPL-2.0-or-later\n", "GPL-2.0-or-later"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IdentifyLicense([]byte(tt.text)), "IdentifyLicense() should identify %s", tt.name)
+		})
+	}
+}
+
+func TestIdentifyLicense_FullText(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		// The GPLv3 names the LGPL in its last paragraph; the LGPLv3 names the GPL throughout
+		{"GPL-3.0.txt", "GPL-3.0-only"},
+		{"LGPL-3.0.txt", "LGPL-3.0-only"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			text, err := os.ReadFile(filepath.Join("testdata", "licenses", tt.file))
+			require.NoError(t, err, "Failed to read license text")
+			assert.Equal(t, tt.want, IdentifyLicense(text), "IdentifyLicense() should identify %s", tt.file)
+		})
+	}
+}
+
+func TestReadLicense(t *testing.T) {
+	gpl, err := os.ReadFile(filepath.Join("testdata", "licenses", "GPL-3.0.txt"))
+	require.NoError(t, err, "Failed to read license text")
+
+	license, err := ReadLicense("tool.tar.gz", makeTarGz(t, map[string][]byte{"tool-1.0.0-linux-amd64/LICENSE": gpl}))
+	require.NoError(t, err, "ReadLicense() should read a tarball")
+	assert.Equal(t, "GPL-3.0-only", license, "ReadLicense() should identify the LICENSE file")
+
+	license, err = ReadLicense("tool.zip", makeZip(t, map[string][]byte{"tool/COPYING": gpl}))
+	require.NoError(t, err, "ReadLicense() should read a zip")
+	assert.Equal(t, "GPL-3.0-only", license, "ReadLicense() should accept COPYING")
+
+	license, err = ReadLicense("tool.tar.gz", makeTarGz(t, map[string][]byte{"tool/tool": []byte("binary")}))
+	require.NoError(t, err, "ReadLicense() should accept archives without a license")
+	assert.Equal(t, NoAssertion, license, "ReadLicense() should make no assertion without a license file")
+}
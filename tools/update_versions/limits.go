@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// tagRegexp matches the release tags the updater accepts: an optional "v", two or three
+// numeric components and optional semver pre-release and build identifiers. Tags come
+// from release APIs and end up in cache file names, URLs and generated Starlark, so
+// anything else (path separators, "..", quotes, whitespace) is rejected.
+var tagRegexp = regexp.MustCompile(`^v?\d+\.\d+(\.\d+)?(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// maxTagLength bounds the length of an accepted tag.
+const maxTagLength = 128
+
+// Size limits. A response or archive entry larger than its limit is an error rather than
+// being read into memory.
+const (
+	// maxListingSize limits release listings and index documents.
+	maxListingSize = 32 << 20
+	// maxChecksumSize limits checksum files and other non-archive assets.
+	maxChecksumSize = 4 << 20
+	// maxArchiveSize limits release archives.
+	maxArchiveSize = 1 << 30
+	// maxArchiveEntrySize limits each file read from an archive once decompressed, so a
+	// small archive cannot expand into unbounded memory.
+	maxArchiveEntrySize = 512 << 20
+)
+
+// defaultRedirectHosts are the hosts release downloads may be redirected to besides the
+// host they were requested from: GitHub serves release assets from these.
+var defaultRedirectHosts = []string{
+	"objects.githubusercontent.com",
+	"release-assets.githubusercontent.com",
+	"github-releases.githubusercontent.com",
+}
+
+// ValidateTag returns an error unless tag is a well-formed release tag.
+func ValidateTag(tag string) error {
+	if len(tag) > maxTagLength {
+		return fmt.Errorf("release tag of %d characters exceeds %d", len(tag), maxTagLength)
+	}
+	if !tagRegexp.MatchString(tag) {
+		return fmt.Errorf("invalid release tag %q", tag)
+	}
+	return nil
+}
+
+// assetSizeLimit returns the size limit of the asset at url: maxArchiveSize for archives,
+// maxChecksumSize otherwise.
+func assetSizeLimit(url string) int64 {
+	name, _, _ := strings.Cut(path.Base(url), "?")
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return maxArchiveSize
+		}
+	}
+	return maxChecksumSize
+}
+
+// readLimited reads r to the end, failing if it holds more than limit bytes.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("size exceeds %d bytes", limit)
+	}
+	return data, nil
+}
+
+// redirectPolicy returns an http.Client CheckRedirect function that follows up to 10
+// redirects to the original request's host or one of allowedHosts, and never from
+// https to http.
+func redirectPolicy(allowedHosts []string) func(*http.Request, []*http.Request) error {
+	allowed := make(map[string]bool)
+	for _, host := range allowedHosts {
+		allowed[strings.ToLower(host)] = true
+	}
+
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		first := via[0].URL
+		if first.Scheme == "https" && req.URL.Scheme != "https" {
+			return fmt.Errorf("refusing redirect from %s to insecure %s://%s", first.Host, req.URL.Scheme, req.URL.Host)
+		}
+		if req.URL.Host != first.Host {
+			if !allowed[strings.ToLower(req.URL.Hostname())] {
+				return fmt.Errorf("refusing redirect from %s to unexpected host %s", first.Host, req.URL.Host)
+			}
+			// net/http drops Authorization on such redirects but not GitLab's token header
+			req.Header.Del("PRIVATE-TOKEN")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"v2.6.1", "2.6.1", "v1.2", "v2.7.0-rc.1", "v1.0.0-beta-2", "v1.0.0+build.5"} {
+		assert.NoError(t, ValidateTag(tag), "ValidateTag(%q) should accept the tag", tag)
+	}
+
+	for _, tag := range []string{
+		"",
+		"../../etc/x",
+		"v1.0.0/../../x",
+		`v1.0.0\x`,
+		"v1.0.0\n",
+		"v1.0.0 ",
+		`v1.0.0"`,
+		"v1.0.0-rc..1",
+		"v1.0.0-",
+		"latest",
+		"v1",
+		"v1.0.0-" + strings.Repeat("a", maxTagLength),
+	} {
+		assert.Error(t, ValidateTag(tag), "ValidateTag(%q) should reject the tag", tag)
+	}
+}
+
+func TestRunner_Run_HostileTags(t *testing.T) {
+	checksums := "fff6666666666666666666666666666666666666666666666666666666666666  golangci-lint-2.6.1-linux-amd64.tar.gz\n"
+	server := newReleaseServer(t, map[string]string{
+		"/api/v1/repos/mirror/golangci-lint/releases": `[
+			{"tag_name": "../../etc/x"},
+			{"tag_name": "v2.6.1/../../../../x"},
+			{"tag_name": "v2.6.1\"]\nfail()"},
+			{"tag_name": "v2.6.1"}
+		]`,
+		"/mirror/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt": checksums,
+	}, nil)
+
+	tempDir := t.TempDir()
+	workspace := filepath.Join(tempDir, "workspace")
+	cacheDir := filepath.Join(workspace, "cache")
+	tool := Tool{
+		Name:       "golangci-lint",
+		Repo:       "mirror/golangci-lint",
+		OutputFile: "versions.bzl",
+		VarPrefix:  "GOLANGCI",
+		Source:     &SourceConfig{Type: SourceGitea, URL: server.URL},
+	}
+	require.NoError(t, tool.Validate(), "Tool.Validate() should succeed")
+	config := Config{
+		Count:         4,
+		CacheDir:      cacheDir,
+		WorkspaceRoot: workspace,
+		Tools:         []Tool{tool},
+	}
+	require.NoError(t, NewRunner(config, NewMockGitHubClient()).Run(context.Background()), "Runner.Run() should skip hostile tags")
+
+	content, err := os.ReadFile(filepath.Join(workspace, "versions.bzl"))
+	require.NoError(t, err, "Failed to read output file")
+	assert.Contains(t, string(content), `"v2.6.1"`, "the valid release should be processed")
+	assert.NotContains(t, string(content), "fail()", "hostile tags should not reach generated files")
+
+	entries, err := os.ReadDir(filepath.Join(cacheDir, tool.CacheSubdir))
+	require.NoError(t, err, "Failed to read cache directory")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"v2.6.1.txt", CacheIndexFile, ReleaseSnapshotFile}, names,
+		"only the valid release should be cached")
+	assert.NoDirExists(t, filepath.Join(tempDir, "etc"), "hostile tags should not escape the cache directory")
+	assert.NoFileExists(t, filepath.Join(tempDir, "x.txt"), "hostile tags should not escape the cache directory")
+}
+
+func TestHTTPGet_SizeLimit(t *testing.T) {
+	body := strings.Repeat("x", 100)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// Flushing before writing the body leaves the length unannounced
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	for _, path := range []string{"/sized", "/chunked"} {
+		data, err := httpGet(context.Background(), clientOrDefault(nil), server.URL+path, nil, 100)
+		require.NoError(t, err, "httpGet(%s) should accept a response within the limit", path)
+		assert.Equal(t, body, string(data), "httpGet(%s) should return the body", path)
+
+		_, err = httpGet(context.Background(), clientOrDefault(nil), server.URL+path, nil, 99)
+		assert.ErrorContains(t, err, "exceeds 99", "httpGet(%s) should reject a response over the limit", path)
+	}
+}
+
+func TestAssetSizeLimit(t *testing.T) {
+	assert.Equal(t, int64(maxArchiveSize), assetSizeLimit("https://example.com/v1/tool-1.0.0-linux-amd64.tar.gz"), "archives should get the archive limit")
+	assert.Equal(t, int64(maxArchiveSize), assetSizeLimit("https://example.com/v1/tool.zip?token=abc"), "queries should be ignored")
+	assert.Equal(t, int64(maxChecksumSize), assetSizeLimit("https://example.com/v1/checksums.txt"), "checksum files should get the checksum limit")
+}
+
+func TestArchiveEntrySizeLimit(t *testing.T) {
+	// 1 MiB of zeros compresses to about a kilobyte
+	files := map[string][]byte{"tool-1.0.0/bin/tool": make([]byte, 1<<20)}
+	tarGz := makeTarGz(t, files)
+	zipped := makeZip(t, files)
+	require.Less(t, len(tarGz), 1<<16, "the archive should be much smaller than its entry")
+
+	match := baseNameIs("tool")
+	_, _, err := readTarGzFile(tarGz, match, 1<<20)
+	require.NoError(t, err, "readTarGzFile() should accept an entry within the limit")
+	_, _, err = readTarGzFile(tarGz, match, 1<<20-1)
+	assert.ErrorContains(t, err, "exceeds 1048575", "readTarGzFile() should reject an entry over the limit")
+	_, _, err = readZipFile(zipped, match, 1<<20)
+	require.NoError(t, err, "readZipFile() should accept an entry within the limit")
+	_, _, err = readZipFile(zipped, match, 1<<20-1)
+	assert.ErrorContains(t, err, "exceeds 1048575", "readZipFile() should reject an entry over the limit")
+
+	_, err = tarGzEntries(tarGz, 1<<20)
+	require.NoError(t, err, "tarGzEntries() should accept entries within the limit")
+	_, err = tarGzEntries(tarGz, 1<<20-1)
+	assert.ErrorContains(t, err, "exceeds 1048575", "tarGzEntries() should reject an entry over the limit")
+	_, err = zipEntries(zipped, 1<<20)
+	require.NoError(t, err, "zipEntries() should accept entries within the limit")
+	_, err = zipEntries(zipped, 1<<20-1)
+	assert.ErrorContains(t, err, "exceeds 1048575", "zipEntries() should reject an entry over the limit")
+}
+
+func TestHTTPClient_Redirects(t *testing.T) {
+	other := newReleaseServer(t, map[string]string{"/asset": "elsewhere"}, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/asset", http.StatusFound)
+		case "/other":
+			http.Redirect(w, r, other.URL+"/asset", http.StatusFound)
+		case "/asset":
+			_, _ = w.Write([]byte("here"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewHTTPClient(HTTPConfig{})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	data, err := httpGet(context.Background(), client, server.URL+"/same", nil, maxChecksumSize)
+	require.NoError(t, err, "a redirect within the host should be followed")
+	assert.Equal(t, "here", string(data), "httpGet() should return the redirect target")
+
+	_, err = httpGet(context.Background(), client, server.URL+"/other", nil, maxChecksumSize)
+	assert.ErrorContains(t, err, "unexpected host", "a redirect to another host should be refused")
+
+	client, err = NewHTTPClient(HTTPConfig{RedirectHosts: []string{"127.0.0.1"}})
+	require.NoError(t, err, "NewHTTPClient() should succeed")
+	data, err = httpGet(context.Background(), client, server.URL+"/other", nil, maxChecksumSize)
+	require.NoError(t, err, "a redirect to an allowed host should be followed")
+	assert.Equal(t, "elsewhere", string(data), "httpGet() should return the redirect target")
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	check := redirectPolicy(defaultRedirectHosts)
+	request := func(url string) *http.Request {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		return req
+	}
+	via := []*http.Request{request("https://github.com/owner/tool/releases/download/v1.0.0/tool.tar.gz")}
+
+	redirected := request("https://objects.githubusercontent.com/asset")
+	redirected.Header.Set("PRIVATE-TOKEN", "secret")
+	assert.NoError(t, check(redirected, via), "GitHub's asset host should be allowed")
+	assert.Empty(t, redirected.Header.Get("PRIVATE-TOKEN"), "tokens should not follow a redirect to another host")
+	assert.Error(t, check(request("https://evil.example.com/asset"), via), "other hosts should be refused")
+	assert.Error(t, check(request("http://github.com/owner/tool/asset"), via), "a downgrade to http should be refused")
+
+	long := make([]*http.Request, 10)
+	for i := range long {
+		long[i] = via[0]
+	}
+	assert.Error(t, check(request("https://github.com/loop"), long), "redirect loops should be stopped")
+}
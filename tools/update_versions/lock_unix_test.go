@@ -0,0 +1,39 @@
+//go:build unix
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockDir(t *testing.T) {
+	dir := t.TempDir()
+
+	unlock, err := lockDir(dir)
+	require.NoError(t, err, "lockDir() should succeed")
+
+	locked := make(chan func())
+	go func() {
+		second, err := lockDir(dir)
+		assert.NoError(t, err, "lockDir() should succeed once released")
+		locked <- second
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("lockDir() should wait while another run holds the lock")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case second := <-locked:
+		second()
+	case <-time.After(5 * time.Second):
+		t.Fatal("lockDir() should acquire the lock once it is released")
+	}
+}
@@ -1,29 +1,230 @@
-// Package main provides a tool for updating golangci-lint version information
-// in Bazel Starlark files by fetching releases from GitHub and generating
-// checksum data for all supported platforms.
+// Package main provides a tool for updating golangci-lint (and other tools')
+// version information in Bazel Starlark files by fetching releases from GitHub
+// and generating checksum data for all supported platforms.
 package main
 
 import (
 	"context"
 	"flag"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
 )
 
+// defaultOutput and defaultToolchainsOutput are used when no --output flag is given.
+const (
+	defaultOutput           = "golangci_lint/private/versions.bzl"
+	defaultToolchainsOutput = "golangci_lint/private/toolchains.bzl"
+)
+
+// outputFlag is one --output flag and the --template that follows it.
+type outputFlag struct {
+	spec     string
+	template string
+}
+
+// outputFlags collects repeated --output flags.
+type outputFlags []outputFlag
+
+// String implements flag.Value.
+func (o *outputFlags) String() string {
+	specs := make([]string, 0, len(*o))
+	for _, f := range *o {
+		specs = append(specs, f.spec)
+	}
+	return strings.Join(specs, ",")
+}
+
+// Set implements flag.Value.
+func (o *outputFlags) Set(value string) error {
+	*o = append(*o, outputFlag{spec: value})
+	return nil
+}
+
+// templateFlag attaches --template to the preceding --output.
+type templateFlag struct {
+	outputs *outputFlags
+}
+
+// String implements flag.Value.
+func (t templateFlag) String() string {
+	return ""
+}
+
+// Set implements flag.Value.
+func (t templateFlag) Set(value string) error {
+	if t.outputs == nil || len(*t.outputs) == 0 {
+		return fmt.Errorf("--template must follow the --output it applies to")
+	}
+	last := &(*t.outputs)[len(*t.outputs)-1]
+	if last.template != "" {
+		return fmt.Errorf("output %s already has a template", last.spec)
+	}
+	last.template = value
+	return nil
+}
+
+var outputs outputFlags
+
+func init() {
+	flag.Var(&outputs, "output", "Output file path as [format:]path; repeat for multiple outputs (default "+defaultOutput+" and "+defaultToolchainsOutput+")")
+	flag.Var(templateFlag{&outputs}, "template", "Template file rendering the preceding --output instead of the built-in Starlark template")
+}
+
 var (
-	count      = flag.Int("count", 10, "Number of versions to process")
+	count      = flag.Int("count", 10, "Number of versions to process, plus the newest release of each older major line")
 	cacheDir   = flag.String("cache-dir", "tools/update_versions/cache/checksums", "Cache directory for checksum files")
-	outputFile = flag.String("output", "golangci_lint/private/versions.bzl", "Output file path for generated Starlark")
+	format     = flag.String("format", FormatStarlark, "Default format for --output: starlark, json, starlark-loader or starlark-toolchains")
+	defaultVer = flag.String("default-version", DefaultVersionLatestStable, "Default version: latest-stable, previous-minor, oldest-supported, or a tag or alias among the processed versions")
+	assetPat   = flag.String("asset-pattern", DefaultAssetPattern, "Release asset name template with {name}, {version}, {os}, {arch} and {ext} placeholders")
+	goVersions = flag.Bool("go-versions", false, "Download each linux/amd64 archive to record the Go version it was built with")
+	noTime     = flag.Bool("no-timestamp", false, "Omit the generation timestamp from output files")
+	offline    = flag.Bool("offline", false, "Use only the cached release listings and checksum files, without network access")
+	changelog  = flag.String("changelog", "", "Also write the Markdown changelog of this run to this file")
+	openPR     = flag.Bool("open-pr", false, "Commit the changes to a branch, push it and open or update a pull request (needs GITHUB_TOKEN)")
+	prBranch   = flag.String("pr-branch", "update-versions", "Branch for --open-pr; reset on every run")
+	prBase     = flag.String("pr-base", "main", "Base branch of the --open-pr pull request")
+	prRemote   = flag.String("pr-remote", "origin", "Git remote --open-pr fetches the base from and pushes to")
+	prRepo     = flag.String("pr-repo", "", "owner/name of the --open-pr repository (default: derived from --pr-remote)")
+	toolsCfg   = flag.String("tools-config", "", "JSON file describing the tools to maintain (overrides --output, --format, --asset-pattern and --default-version)")
+	httpOpts   = addHTTPFlags(flag.CommandLine)
 )
 
+// httpFlags holds the HTTP client flags shared by the update and the subcommands that
+// download.
+type httpFlags struct {
+	proxy          *string
+	caBundle       *string
+	requestTimeout *time.Duration
+	timeout        *time.Duration
+	redirectHosts  *string
+}
+
+// addHTTPFlags registers the HTTP client flags on fs.
+func addHTTPFlags(fs *flag.FlagSet) *httpFlags {
+	return &httpFlags{
+		proxy:          fs.String("proxy", "", "Proxy URL for all requests (default: from HTTPS_PROXY, HTTP_PROXY and NO_PROXY)"),
+		caBundle:       fs.String("ca-bundle", "", "PEM file of CA certificates to trust in addition to the system roots"),
+		requestTimeout: fs.Duration("request-timeout", 5*time.Minute, "Time limit of each HTTP request, including its body (0 for none)"),
+		timeout:        fs.Duration("timeout", 0, "Time limit of the whole run (0 for none)"),
+		redirectHosts:  fs.String("redirect-hosts", "", "Comma-separated hosts downloads may be redirected to besides the requested host and GitHub's asset hosts"),
+	}
+}
+
+// configure returns the HTTP client described by the flags and a context bounded by
+// --timeout.
+func (f *httpFlags) configure() (*http.Client, context.Context, context.CancelFunc) {
+	var redirectHosts []string
+	for _, host := range strings.Split(*f.redirectHosts, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			redirectHosts = append(redirectHosts, host)
+		}
+	}
+	client, err := NewHTTPClient(HTTPConfig{
+		ProxyURL:       *f.proxy,
+		CABundle:       *f.caBundle,
+		RequestTimeout: *f.requestTimeout,
+		RedirectHosts:  redirectHosts,
+	})
+	if err != nil {
+		log.Fatalf("Invalid HTTP configuration: %v", err)
+	}
+	if *f.timeout > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), *f.timeout)
+		return client, ctx, cancel
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return client, ctx, cancel
+}
+
+// subcommands maps the name of each subcommand to its entry point, which parses the
+// remaining arguments. Without a subcommand the version files are updated.
+var subcommands = map[string]func(args []string){
+	"fetch": runFetch,
+	"prune": runPrune,
+	"sbom":  runSBOM,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := subcommands[os.Args[1]]; ok {
+			cmd(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
 	if *count <= 0 {
 		log.Fatal("count must be positive")
 	}
+	if *offline && *openPR {
+		log.Fatal("--offline cannot be combined with --open-pr")
+	}
+	httpClient, ctx, cancel := httpOpts.configure()
+	defer cancel()
+
+	workspaceRoot := findWorkspaceRoot()
+
+	// Load tool descriptors, defaulting to golangci-lint
+	tools, err := loadTools(workspaceRoot)
+	if err != nil {
+		log.Fatalf("Invalid tool configuration: %v", err)
+	}
+
+	// Create configuration
+	config := Config{
+		Count:            *count,
+		CacheDir:         *cacheDir,
+		WorkspaceRoot:    workspaceRoot,
+		Tools:            tools,
+		RecordGoVersions: *goVersions,
+		HTTPClient:       httpClient,
+		OmitTimestamp:    *noTime,
+		Offline:          *offline,
+		ChangelogWriter:  os.Stdout,
+		ChangelogFile:    *changelog,
+		StepSummaryFile:  os.Getenv("GITHUB_STEP_SUMMARY"),
+	}
 
-	// Determine workspace root
+	// Initialize GitHub client
+	client := NewGitHubClient(httpClient)
+
+	// Create runner and execute
+	runner := NewRunner(config, client)
+
+	if err := runner.Run(ctx); err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	if *openPR {
+		token := os.Getenv("GITHUB_TOKEN")
+		if token == "" {
+			log.Fatal("--open-pr requires GITHUB_TOKEN")
+		}
+		prs, err := NewPullRequestClient(httpClient, os.Getenv("GITHUB_API_URL"), token)
+		if err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+		err = runner.OpenPullRequest(ctx, PullRequestConfig{
+			Remote: *prRemote,
+			Base:   *prBase,
+			Branch: *prBranch,
+			Repo:   *prRepo,
+		}, prs)
+		if err != nil {
+			log.Fatalf("Failed to open pull request: %v", err)
+		}
+	}
+}
+
+// findWorkspaceRoot returns the directory relative paths are resolved against.
+func findWorkspaceRoot() string {
 	// When running via `bazel run`, Bazel sets BUILD_WORKSPACE_DIRECTORY
 	workspaceRoot := os.Getenv("BUILD_WORKSPACE_DIRECTORY")
 	if workspaceRoot == "" {
@@ -34,23 +235,196 @@ func main() {
 			log.Fatalf("Failed to get working directory: %v", err)
 		}
 	}
+	return workspaceRoot
+}
 
-	// Create configuration
-	config := Config{
-		Count:         *count,
-		CacheDir:      *cacheDir,
-		OutputFile:    *outputFile,
-		WorkspaceRoot: workspaceRoot,
+// runSBOM implements the sbom subcommand: it describes the binaries of the pinned
+// versions of every tool as an SPDX or CycloneDX document.
+func runSBOM(args []string) {
+	fs := flag.NewFlagSet("sbom", flag.ExitOnError)
+	sbomFormat := fs.String("format", SBOMFormatSPDX, "SBOM format: spdx or cyclonedx")
+	sbomOut := fs.String("out", "", "Write the SBOM to this file instead of stdout")
+	sbomCache := fs.String("cache-dir", "tools/update_versions/cache/checksums", "Cache directory for checksum and license files")
+	sbomTools := fs.String("tools-config", "", "JSON file describing the tools to describe (default golangci-lint)")
+	licenses := fs.Bool("licenses", false, "Download the linux/amd64 archive of versions without a cached license to read it")
+	sbomHTTP := addHTTPFlags(fs)
+	_ = fs.Parse(args)
+
+	httpClient, ctx, cancel := sbomHTTP.configure()
+	defer cancel()
+	workspaceRoot := findWorkspaceRoot()
+	tools := subcommandTools(workspaceRoot, *sbomTools)
+
+	runner := NewRunner(Config{
+		CacheDir:       *sbomCache,
+		WorkspaceRoot:  workspaceRoot,
+		Tools:          tools,
+		RecordLicenses: *licenses,
+		HTTPClient:     httpClient,
+	}, NewGitHubClient(httpClient))
+	sbom, err := runner.SBOM(ctx, *sbomFormat)
+	if err != nil {
+		log.Fatalf("Error: %v", err)
 	}
 
-	// Initialize GitHub client
-	client := NewGitHubClient()
+	if *sbomOut == "" {
+		if _, err := os.Stdout.Write(sbom); err != nil {
+			log.Fatalf("Failed to write SBOM: %v", err)
+		}
+		return
+	}
+	path := resolveWorkspacePath(workspaceRoot, *sbomOut)
+	if _, err := writeFileAtomic(path, sbom); err != nil {
+		log.Fatalf("Failed to write SBOM: %v", err)
+	}
+	log.Printf("Wrote %s", path)
+}
 
-	// Create runner and execute
-	runner := NewRunner(config, client)
-	ctx := context.Background()
+// runFetch implements the fetch subcommand: it installs the pinned binary of a version
+// (default: the default version) for the host and prints its path.
+func runFetch(args []string) {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: update_versions fetch [flags] [version]")
+		fs.PrintDefaults()
+	}
+	fetchTools := fs.String("tools-config", "", "JSON file describing the tools (default golangci-lint)")
+	fetchTool := fs.String("tool", "", "Name of the tool to fetch (default: the first configured tool)")
+	fetchDest := fs.String("dest", "", "Directory to install into (default: a per-user cache directory)")
+	fetchOS := fs.String("os", runtime.GOOS, "Target OS")
+	fetchArch := fs.String("arch", runtime.GOARCH, "Target architecture")
+	fetchHTTP := addHTTPFlags(fs)
+	_ = fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
 
-	if err := runner.Run(ctx); err != nil {
+	httpClient, ctx, cancel := fetchHTTP.configure()
+	defer cancel()
+
+	workspaceRoot := findWorkspaceRoot()
+	tools := subcommandTools(workspaceRoot, *fetchTools)
+	tool := tools[0]
+	if *fetchTool != "" {
+		found := false
+		for _, t := range tools {
+			if t.Name == *fetchTool {
+				tool, found = t, true
+				break
+			}
+		}
+		if !found {
+			log.Fatalf("Unknown tool %q", *fetchTool)
+		}
+	}
+
+	dest := *fetchDest
+	if dest == "" {
+		var err error
+		if dest, err = DefaultFetchDir(); err != nil {
+			log.Fatalf("Error: %v", err)
+		}
+	}
+
+	runner := NewRunner(Config{WorkspaceRoot: workspaceRoot, Tools: []Tool{tool}, HTTPClient: httpClient}, NewGitHubClient(httpClient))
+	path, err := runner.Fetch(ctx, tool, fs.Arg(0), *fetchOS, *fetchArch, resolveWorkspacePath(workspaceRoot, dest))
+	if err != nil {
 		log.Fatalf("Error: %v", err)
 	}
+	fmt.Println(path)
+}
+
+// runPrune implements the prune subcommand: it removes the cache files of versions no
+// longer in any tool's generated data.
+func runPrune(args []string) {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	pruneCache := fs.String("cache-dir", "tools/update_versions/cache/checksums", "Cache directory to prune")
+	pruneTools := fs.String("tools-config", "", "JSON file describing the tools whose caches to prune (default golangci-lint)")
+	keep := fs.String("keep", "", "Comma-separated tags to keep even if not in the generated data")
+	keepDays := fs.Int("keep-days", 0, "Keep entries downloaded less than this many days ago")
+	dryRun := fs.Bool("dry-run", false, "List the entries that would be removed without removing them")
+	_ = fs.Parse(args)
+
+	var keepTags []string
+	for _, tag := range strings.Split(*keep, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			keepTags = append(keepTags, tag)
+		}
+	}
+
+	workspaceRoot := findWorkspaceRoot()
+	tools := subcommandTools(workspaceRoot, *pruneTools)
+	runner := NewRunner(Config{CacheDir: *pruneCache, WorkspaceRoot: workspaceRoot, Tools: tools}, nil)
+
+	verb := "Removed"
+	if *dryRun {
+		verb = "Would remove"
+	}
+	failed := false
+	for _, tool := range tools {
+		paths, err := runner.Prune(tool, PruneOptions{Keep: keepTags, KeepDays: *keepDays, DryRun: *dryRun, Now: time.Now()})
+		for _, path := range paths {
+			fmt.Printf("%s %s\n", verb, path)
+		}
+		if err != nil {
+			log.Printf("Error: %s: %v", tool.Name, err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// subcommandTools returns the tools of a --tools-config file, or golangci-lint if path is empty.
+func subcommandTools(workspaceRoot, path string) []Tool {
+	if path == "" {
+		return []Tool{DefaultTool()}
+	}
+	tools, err := LoadToolsConfig(resolveWorkspacePath(workspaceRoot, path))
+	if err != nil {
+		log.Fatalf("Invalid tool configuration: %v", err)
+	}
+	return tools
+}
+
+// resolveWorkspacePath resolves a relative path against the workspace root.
+func resolveWorkspacePath(workspaceRoot, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(workspaceRoot, path)
+}
+
+// loadTools returns the tools from --tools-config, or golangci-lint configured by
+// --output, --format, --asset-pattern and --default-version. A relative config path is resolved against the workspace root.
+func loadTools(workspaceRoot string) ([]Tool, error) {
+	if *toolsCfg != "" {
+		return LoadToolsConfig(resolveWorkspacePath(workspaceRoot, *toolsCfg))
+	}
+
+	if !isOutputFormat(*format) {
+		return nil, fmt.Errorf("unknown format %q", *format)
+	}
+
+	specs := outputs
+	if len(specs) == 0 {
+		specs = outputFlags{{spec: defaultOutput}, {spec: FormatStarlarkToolchains + ":" + defaultToolchainsOutput}}
+	}
+
+	tool := DefaultTool()
+	tool.OutputFile = ""
+	tool.Outputs = nil
+	for _, f := range specs {
+		out := ParseOutputSpec(f.spec, *format)
+		out.Template = f.template
+		tool.Outputs = append(tool.Outputs, out)
+	}
+	tool.AssetPattern = *assetPat
+	tool.DefaultVersion = *defaultVer
+	if err := tool.Validate(); err != nil {
+		return nil, err
+	}
+	return []Tool{tool}, nil
 }
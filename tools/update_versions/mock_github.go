@@ -5,34 +5,48 @@ import (
 	"fmt"
 )
 
-// MockGitHubClient is a mock implementation of GitHubAPI for testing.
+// MockGitHubClient is a mock implementation of ReleaseSource for testing.
+// Asset URLs follow the github.com layout.
 type MockGitHubClient struct {
-	Releases      []Release
-	AssetContents map[string][]byte
+	Releases         []Release
+	RepoReleases     map[string][]Release
+	AssetContents    map[string][]byte
 	GetReleasesError error
-	DownloadError error
+	DownloadError    error
 }
 
 // NewMockGitHubClient creates a new mock GitHub client.
 func NewMockGitHubClient() *MockGitHubClient {
 	return &MockGitHubClient{
 		Releases:      []Release{},
+		RepoReleases:  make(map[string][]Release),
 		AssetContents: make(map[string][]byte),
 	}
 }
 
 // GetLatestReleases returns the pre-configured releases or an error.
-func (m *MockGitHubClient) GetLatestReleases(_ context.Context, count int) ([]Release, error) {
+// Releases added for a specific repository take precedence over the default list.
+func (m *MockGitHubClient) GetLatestReleases(_ context.Context, repo string, count int) ([]Release, error) {
 	if m.GetReleasesError != nil {
 		return nil, m.GetReleasesError
 	}
 
+	releases, ok := m.RepoReleases[repo]
+	if !ok {
+		releases = m.Releases
+	}
+
 	// Return up to 'count' releases
-	if count > len(m.Releases) {
-		count = len(m.Releases)
+	if count > len(releases) {
+		count = len(releases)
 	}
 
-	return m.Releases[:count], nil
+	return releases[:count], nil
+}
+
+// AssetURL returns the github.com download URL of a release asset.
+func (m *MockGitHubClient) AssetURL(repo, tag, name string) string {
+	return fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", repo, tag, name)
 }
 
 // DownloadAsset returns the pre-configured asset content for the given URL or an error.
@@ -54,6 +68,16 @@ func (m *MockGitHubClient) AddRelease(tag string) {
 	m.Releases = append(m.Releases, Release{TagName: tag})
 }
 
+// AddReleaseWithAssets adds a release with an asset list to the mock client.
+func (m *MockGitHubClient) AddReleaseWithAssets(tag string, assets ...Asset) {
+	m.Releases = append(m.Releases, Release{TagName: tag, Assets: assets})
+}
+
+// AddRepoRelease adds a release for a specific repository to the mock client.
+func (m *MockGitHubClient) AddRepoRelease(repo, tag string) {
+	m.RepoReleases[repo] = append(m.RepoReleases[repo], Release{TagName: tag})
+}
+
 // AddAsset adds asset content for a specific URL.
 func (m *MockGitHubClient) AddAsset(url string, content []byte) {
 	m.AssetContents[url] = content
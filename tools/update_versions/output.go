@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Output formats accepted in Output.Format.
+const (
+	// FormatStarlark renders the full data as a .bzl file (the default).
+	FormatStarlark = "starlark"
+	// FormatJSON writes a schema-versioned JSON document.
+	FormatJSON = "json"
+	// FormatStarlarkLoader writes a .bzl file that reads a JSON output via json.decode.
+	FormatStarlarkLoader = "starlark-loader"
+	// FormatStarlarkToolchains writes a .bzl file declaring an archive repository per version
+	// and platform and the toolchain() targets that register them.
+	FormatStarlarkToolchains = "starlark-toolchains"
+)
+
+// JSONSchemaVersion is the version of the JSON document layout. Bump it on
+// incompatible changes so consumers can reject data they do not understand.
+const JSONSchemaVersion = 1
+
+// Output is a file generated from the version data.
+type Output struct {
+	// Path is the output file path, relative to the workspace root.
+	Path string `json:"path"`
+	// Format is one of starlark (default), json, starlark-loader or starlark-toolchains.
+	Format string `json:"format,omitempty"`
+	// Data is the workspace-relative path of the JSON output a starlark-loader reads.
+	// Defaults to the tool's only json output.
+	Data string `json:"data,omitempty"`
+	// Template is the workspace-relative path of a template that replaces the built-in
+	// one for a starlark output. See README.md for the data and functions it can use.
+	Template string `json:"template,omitempty"`
+}
+
+// JSONData is the JSON document written for FormatJSON.
+type JSONData struct {
+	SchemaVersion   int               `json:"schema_version"`
+	Tool            string            `json:"tool"`
+	GeneratedAt     string            `json:"generated_at,omitempty"`
+	DefaultVersion  string            `json:"default_version"`
+	DefaultVersions map[string]string `json:"default_versions,omitempty"` // major -> tag
+	Aliases         map[string]string `json:"aliases,omitempty"`          // alias -> tag
+	Platforms       []JSONPlatform    `json:"platforms,omitempty"`
+	Versions        []JSONVersionData `json:"versions"`
+}
+
+// JSONPlatform is a platform published by at least one version, with its Bazel constraints.
+type JSONPlatform struct {
+	OS          string   `json:"os"`
+	Arch        string   `json:"arch"`
+	GOARM       string   `json:"goarm,omitempty"`
+	Constraints []string `json:"constraints"`
+}
+
+// JSONVersionData is a single version in the JSON document, newest first.
+type JSONVersionData struct {
+	Tag        string                       `json:"tag"`
+	GoVersion  string                       `json:"go_version,omitempty"`
+	Checksums  map[string]map[string]string `json:"checksums"` // os -> arch -> sha256
+	Provenance *Provenance                  `json:"provenance,omitempty"`
+}
+
+// loaderData holds the data for rendering loader.bzl.tmpl.
+type loaderData struct {
+	*TemplateData
+	DataLabel     string
+	SchemaVersion int
+}
+
+// ParseOutputSpec parses a command-line output of the form "[format:]path".
+// Without a recognised format prefix, defaultFormat is used.
+func ParseOutputSpec(spec, defaultFormat string) Output {
+	if format, p, ok := strings.Cut(spec, ":"); ok && isOutputFormat(format) {
+		return Output{Path: p, Format: format}
+	}
+	return Output{Path: spec, Format: defaultFormat}
+}
+
+// isOutputFormat reports whether format is a known output format.
+func isOutputFormat(format string) bool {
+	switch format {
+	case FormatStarlark, FormatJSON, FormatStarlarkLoader, FormatStarlarkToolchains:
+		return true
+	}
+	return false
+}
+
+// validateOutputs checks formats and resolves the data file of starlark-loader outputs.
+func validateOutputs(outputs []Output) error {
+	var jsonOutputs []string
+	for i := range outputs {
+		if outputs[i].Format == "" {
+			outputs[i].Format = FormatStarlark
+		}
+		if outputs[i].Path == "" {
+			return fmt.Errorf("output path is required")
+		}
+		if !isOutputFormat(outputs[i].Format) {
+			return fmt.Errorf("output %s: unknown format %q", outputs[i].Path, outputs[i].Format)
+		}
+		if outputs[i].Template != "" && outputs[i].Format != FormatStarlark {
+			return fmt.Errorf("output %s: template is only supported for starlark outputs", outputs[i].Path)
+		}
+		if outputs[i].Format == FormatJSON {
+			jsonOutputs = append(jsonOutputs, outputs[i].Path)
+		}
+	}
+
+	for i := range outputs {
+		if outputs[i].Format != FormatStarlarkLoader {
+			continue
+		}
+		if outputs[i].Data == "" {
+			if len(jsonOutputs) != 1 {
+				return fmt.Errorf("output %s: starlark-loader needs data set when there is not exactly one json output", outputs[i].Path)
+			}
+			outputs[i].Data = jsonOutputs[0]
+		}
+		if filepath.IsAbs(outputs[i].Data) {
+			return fmt.Errorf("output %s: data must be relative to the workspace root", outputs[i].Path)
+		}
+	}
+
+	return nil
+}
+
+// GenerateOutput writes data to outputPath in the output's format and reports whether the
+// file changed. out.Template, if set, must already be resolved to a readable path.
+func GenerateOutput(data *TemplateData, out Output, outputPath string) (bool, error) {
+	switch out.Format {
+	case "", FormatStarlark:
+		if out.Template != "" {
+			return GenerateCustomFile(data, out.Template, outputPath)
+		}
+		return GenerateStarlarkFile(data, outputPath)
+	case FormatJSON:
+		return GenerateJSONFile(data, outputPath)
+	case FormatStarlarkLoader:
+		return GenerateStarlarkLoaderFile(data, workspaceLabel(out.Data), outputPath)
+	case FormatStarlarkToolchains:
+		return GenerateToolchainsFile(data, outputPath)
+	default:
+		return false, fmt.Errorf("unknown output format %q", out.Format)
+	}
+}
+
+// GenerateCustomFile renders a user-supplied template to outputPath.
+func GenerateCustomFile(data *TemplateData, templatePath, outputPath string) (bool, error) {
+	content, err := RenderCustomTemplate(templatePath, data)
+	if err != nil {
+		return false, err
+	}
+	if err := CheckStarlarkSyntax(filepath.Base(outputPath), content); err != nil {
+		return false, err
+	}
+	return writeStarlarkFileAtomic(outputPath, content)
+}
+
+// GenerateJSONFile writes the version data as a schema-versioned JSON document.
+func GenerateJSONFile(data *TemplateData, outputPath string) (bool, error) {
+	content, err := RenderJSON(data)
+	if err != nil {
+		return false, err
+	}
+	return writeFileAtomic(outputPath, content)
+}
+
+// RenderJSON renders the version data as an indented JSON document.
+func RenderJSON(data *TemplateData) ([]byte, error) {
+	data = withToolDefaults(data)
+
+	doc := JSONData{
+		SchemaVersion:  JSONSchemaVersion,
+		Tool:           data.ToolName,
+		GeneratedAt:    data.GeneratedAt,
+		DefaultVersion: data.DefaultVersion,
+		Versions:       make([]JSONVersionData, 0, len(data.Versions)),
+	}
+	if len(data.DefaultVersions) > 0 {
+		doc.DefaultVersions = make(map[string]string, len(data.DefaultVersions))
+		for _, d := range data.DefaultVersions {
+			doc.DefaultVersions[d.Major] = d.Version
+		}
+	}
+	if len(data.Aliases) > 0 {
+		doc.Aliases = make(map[string]string, len(data.Aliases))
+		for _, a := range data.Aliases {
+			doc.Aliases[a.Name] = a.Version
+		}
+	}
+	for _, p := range data.Platforms {
+		doc.Platforms = append(doc.Platforms, JSONPlatform{OS: p.OS, Arch: p.Arch, GOARM: p.GOARM, Constraints: p.Constraints})
+	}
+	for _, v := range data.Versions {
+		doc.Versions = append(doc.Versions, JSONVersionData{
+			Tag:        v.Tag,
+			GoVersion:  v.GoVersion,
+			Checksums:  v.ChecksumsByOS,
+			Provenance: v.Provenance,
+		})
+	}
+
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// GenerateStarlarkLoaderFile writes a .bzl file that reads the JSON document at dataLabel.
+func GenerateStarlarkLoaderFile(data *TemplateData, dataLabel, outputPath string) (bool, error) {
+	content, err := renderTemplate("loader.bzl.tmpl", &loaderData{
+		TemplateData:  withToolDefaults(data),
+		DataLabel:     dataLabel,
+		SchemaVersion: JSONSchemaVersion,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := CheckStarlarkSyntax(filepath.Base(outputPath), content); err != nil {
+		return false, err
+	}
+	return writeStarlarkFileAtomic(outputPath, content)
+}
+
+// workspaceLabel converts a workspace-relative file path into a main-repository label,
+// assuming the file's directory is its Bazel package (e.g. a/b/c.json -> //a/b:c.json).
+func workspaceLabel(p string) string {
+	p = path.Clean(filepath.ToSlash(p))
+	dir := path.Dir(p)
+	if dir == "." {
+		dir = ""
+	}
+	return "//" + dir + ":" + path.Base(p)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOutputSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Output
+	}{
+		{"versions.bzl", Output{Path: "versions.bzl", Format: FormatStarlark}},
+		{"json:data/versions.json", Output{Path: "data/versions.json", Format: FormatJSON}},
+		{"starlark-loader:private/versions.bzl", Output{Path: "private/versions.bzl", Format: FormatStarlarkLoader}},
+		{"C:/out/versions.bzl", Output{Path: "C:/out/versions.bzl", Format: FormatStarlark}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseOutputSpec(tt.spec, FormatStarlark), "ParseOutputSpec() should split format prefix")
+		})
+	}
+}
+
+func TestValidateOutputs(t *testing.T) {
+	t.Run("loader defaults to the only json output", func(t *testing.T) {
+		outputs := []Output{
+			{Path: "private/versions.json", Format: FormatJSON},
+			{Path: "private/versions.bzl", Format: FormatStarlarkLoader},
+		}
+
+		require.NoError(t, validateOutputs(outputs), "validateOutputs() should succeed")
+		assert.Equal(t, "private/versions.json", outputs[1].Data, "validateOutputs() should default loader data")
+	})
+
+	t.Run("defaults format to starlark", func(t *testing.T) {
+		outputs := []Output{{Path: "versions.bzl"}}
+
+		require.NoError(t, validateOutputs(outputs), "validateOutputs() should succeed")
+		assert.Equal(t, FormatStarlark, outputs[0].Format, "validateOutputs() should default format")
+	})
+
+	t.Run("rejects invalid outputs", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			outputs []Output
+		}{
+			{"unknown format", []Output{{Path: "out.yaml", Format: "yaml"}}},
+			{"missing path", []Output{{Format: FormatJSON}}},
+			{"loader without json", []Output{{Path: "out.bzl", Format: FormatStarlarkLoader}}},
+			{"ambiguous loader data", []Output{
+				{Path: "a.json", Format: FormatJSON},
+				{Path: "b.json", Format: FormatJSON},
+				{Path: "out.bzl", Format: FormatStarlarkLoader},
+			}},
+			{"absolute loader data", []Output{{Path: "out.bzl", Format: FormatStarlarkLoader, Data: "/tmp/versions.json"}}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Error(t, validateOutputs(tt.outputs), "validateOutputs() should return error")
+			})
+		}
+	})
+}
+
+func TestRenderJSON(t *testing.T) {
+	data := &TemplateData{
+		ToolName:       "golangci-lint",
+		VarPrefix:      "GOLANGCI",
+		GeneratedAt:    "2025-01-01T00:00:00Z",
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{
+			{
+				Tag:           "v2.6.1",
+				GoVersion:     "1.25.3",
+				ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "abc123"}},
+			},
+			{
+				Tag:           "v2.6.0",
+				ChecksumsByOS: map[string]map[string]string{"darwin": {"arm64": "def456"}},
+			},
+		},
+	}
+
+	content, err := RenderJSON(data)
+	require.NoError(t, err, "RenderJSON() should succeed")
+
+	var doc JSONData
+	require.NoError(t, json.Unmarshal(content, &doc), "RenderJSON() should produce valid JSON")
+
+	assert.Equal(t, JSONSchemaVersion, doc.SchemaVersion, "document should carry the schema version")
+	assert.Equal(t, "golangci-lint", doc.Tool, "document should name the tool")
+	assert.Equal(t, "v2.6.1", doc.DefaultVersion, "document should record the default version")
+	require.Len(t, doc.Versions, 2, "document should contain all versions")
+	assert.Equal(t, "v2.6.1", doc.Versions[0].Tag, "versions should keep newest-first order")
+	assert.Equal(t, "1.25.3", doc.Versions[0].GoVersion, "document should record Go versions")
+	assert.Equal(t, "def456", doc.Versions[1].Checksums["darwin"]["arm64"], "document should contain checksums by os and arch")
+}
+
+func TestWorkspaceLabel(t *testing.T) {
+	assert.Equal(t, "//golangci_lint/private:versions.json", workspaceLabel("golangci_lint/private/versions.json"),
+		"workspaceLabel() should use the directory as package")
+	assert.Equal(t, "//:versions.json", workspaceLabel("versions.json"),
+		"workspaceLabel() should map top-level files to the root package")
+}
+
+func TestGenerateStarlarkLoaderFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "versions.bzl")
+	data := &TemplateData{ToolName: "golangci-lint", VarPrefix: "GOLANGCI"}
+
+	_, err := GenerateStarlarkLoaderFile(data, "//golangci_lint/private:versions.json", outputPath)
+	require.NoError(t, err, "GenerateStarlarkLoaderFile() should succeed")
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err, "Failed to read output file")
+
+	assert.Contains(t, string(content), `GOLANGCI_DATA = Label("//golangci_lint/private:versions.json")`, "loader should reference the JSON label")
+	assert.Contains(t, string(content), "json.decode", "loader should decode the JSON document")
+	assert.Contains(t, string(content), "def get_golangci_version_info(ctx, version = None):", "loader should define the lookup function")
+}
+
+func TestRunner_Run_MultipleOutputs(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte("aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"),
+	)
+
+	tool := DefaultTool()
+	tool.OutputFile = ""
+	tool.Outputs = []Output{
+		{Path: "private/versions.json", Format: FormatJSON},
+		{Path: "private/versions.bzl", Format: FormatStarlarkLoader},
+		{Path: "private/legacy.bzl"},
+	}
+	require.NoError(t, tool.Validate(), "Tool.Validate() should succeed")
+
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{tool},
+	}
+
+	require.NoError(t, NewRunner(config, mock).Run(context.Background()), "Runner.Run() should succeed")
+
+	jsonContent, err := os.ReadFile(filepath.Join(tempDir, "private/versions.json"))
+	require.NoError(t, err, "JSON output should be written")
+	assert.Contains(t, string(jsonContent), "aaa1111111111111111111111111111111111111111111111111111111111111", "JSON output should contain checksums")
+
+	loaderContent, err := os.ReadFile(filepath.Join(tempDir, "private/versions.bzl"))
+	require.NoError(t, err, "loader output should be written")
+	assert.Contains(t, string(loaderContent), `Label("//private:versions.json")`, "loader should read the JSON output")
+
+	legacyContent, err := os.ReadFile(filepath.Join(tempDir, "private/legacy.bzl"))
+	require.NoError(t, err, "starlark output should be written")
+	assert.Contains(t, string(legacyContent), "GOLANGCI_VERSIONS", "starlark output should contain version data")
+}
+
+func TestGenerateOutput_CustomTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	templatePath := filepath.Join(tempDir, "repos.bzl.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte(`{{- range .Versions}}{{$tag := .Tag}}
+{{- range $os, $archs := .ChecksumsByOS}}{{range $arch, $sha := $archs}}
+http_archive(
+    name = {{quote (printf "%s_%s_%s" $.ToolName $os $arch)}},
+    url = {{quote (renderURL $.URLTemplate $tag $os $arch)}},
+    integrity = {{quote (sri $sha)}},
+    target_compatible_with = [{{range constraints $os $arch}}{{quote .}}, {{end}}],
+)
+{{- end}}{{end}}{{end}}
+`), 0644), "Failed to write template")
+
+	data := &TemplateData{
+		ToolName:    "golangci-lint",
+		URLTemplate: "https://example.com/{tag}/golangci-lint-{version}-{os}-{arch}.{ext}",
+		Versions: []VersionData{{
+			Tag:           "v2.6.1",
+			ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}},
+		}},
+	}
+
+	outputPath := filepath.Join(tempDir, "repos.bzl")
+	_, err := GenerateOutput(data, Output{Path: "repos.bzl", Format: FormatStarlark, Template: templatePath}, outputPath)
+	require.NoError(t, err, "GenerateOutput() should render the custom template")
+
+	content, err := os.ReadFile(outputPath)
+	require.NoError(t, err, "Failed to read output file")
+	assert.Equal(t, `http_archive(
+    name = "golangci-lint_linux_amd64",
+    url = "https://example.com/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz",
+    integrity = "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=",
+    target_compatible_with = ["@platforms//os:linux", "@platforms//cpu:x86_64"],
+)
+`, string(content), "custom template should have access to the function library and be formatted")
+}
+
+func TestGenerateOutput_CustomTemplateErrors(t *testing.T) {
+	tempDir := t.TempDir()
+	outputPath := filepath.Join(tempDir, "out.bzl")
+
+	_, err := GenerateOutput(&TemplateData{}, Output{Path: "out.bzl", Template: filepath.Join(tempDir, "missing.tmpl")}, outputPath)
+	assert.Error(t, err, "GenerateOutput() should fail for a missing template")
+
+	templatePath := filepath.Join(tempDir, "bad.tmpl")
+	require.NoError(t, os.WriteFile(templatePath, []byte(`{{sri "nothex"}}`), 0644), "Failed to write template")
+	_, err = GenerateOutput(&TemplateData{}, Output{Path: "out.bzl", Template: templatePath}, outputPath)
+	assert.Error(t, err, "GenerateOutput() should surface template function errors")
+	assert.NoFileExists(t, outputPath, "GenerateOutput() should not write output on error")
+
+	assert.Error(t, validateOutputs([]Output{{Path: "out.json", Format: FormatJSON, Template: templatePath}}),
+		"validateOutputs() should reject templates for non-starlark outputs")
+}
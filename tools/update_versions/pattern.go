@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultAssetPattern is the asset naming template used by golangci-lint releases.
+// Example: golangci-lint-2.6.1-linux-amd64.tar.gz.
+const DefaultAssetPattern = "{name}-{version}-{os}-{arch}.{ext}"
+
+// DefaultToolName is the value substituted for {name} when no other tool is configured.
+const DefaultToolName = "golangci-lint"
+
+// placeholderPatterns maps each supported placeholder to the regular expression it expands to.
+var placeholderPatterns = map[string]string{
+	// Semver-like versions with optional "v" prefix, prerelease and build metadata,
+	// e.g. 2.6.1, v2.7.0-rc.1, 1.0.0+build.5.
+	"version": `v?\d+(?:\.\d+)*(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?`,
+	"os":      `\w+`,
+	"arch":    `\w+`,
+	"ext":     `tar\.gz|tar\.xz|tgz|zip`,
+}
+
+// placeholderRegexp matches a single {placeholder} in an asset pattern template.
+var placeholderRegexp = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// defaultAssetPattern is compiled once and used by ExtractPlatformFromFilename and ParseChecksumFile.
+var defaultAssetPattern = MustCompileAssetPattern(DefaultToolName, DefaultAssetPattern)
+
+// AssetPattern is a compiled asset naming template used to extract platforms from file names.
+type AssetPattern struct {
+	name     string
+	template string
+	re       *regexp.Regexp
+}
+
+// CompileAssetPattern compiles an asset naming template.
+// Supported placeholders are {name}, {version}, {os}, {arch} and {ext}; {os} and {arch} are required.
+// Each placeholder may appear at most once. Everything else is matched literally.
+func CompileAssetPattern(name, template string) (*AssetPattern, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+
+	seen := make(map[string]bool)
+	last := 0
+	for _, loc := range placeholderRegexp.FindAllStringSubmatchIndex(template, -1) {
+		expr.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		last = loc[1]
+
+		placeholder := template[loc[2]:loc[3]]
+		if seen[placeholder] {
+			return nil, fmt.Errorf("asset pattern %q: placeholder {%s} used more than once", template, placeholder)
+		}
+		seen[placeholder] = true
+
+		if placeholder == "name" {
+			if name == "" {
+				return nil, fmt.Errorf("asset pattern %q: {name} used but no tool name given", template)
+			}
+			expr.WriteString(regexp.QuoteMeta(name))
+			continue
+		}
+
+		sub, ok := placeholderPatterns[placeholder]
+		if !ok {
+			return nil, fmt.Errorf("asset pattern %q: unknown placeholder {%s}", template, placeholder)
+		}
+		fmt.Fprintf(&expr, "(?P<%s>%s)", placeholder, sub)
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteString("$")
+
+	if !seen["os"] || !seen["arch"] {
+		return nil, fmt.Errorf("asset pattern %q: {os} and {arch} placeholders are required", template)
+	}
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("asset pattern %q: %w", template, err)
+	}
+
+	return &AssetPattern{
+		name:     name,
+		template: template,
+		re:       re,
+	}, nil
+}
+
+// MustCompileAssetPattern is like CompileAssetPattern but panics if the template is invalid.
+func MustCompileAssetPattern(name, template string) *AssetPattern {
+	p, err := CompileAssetPattern(name, template)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the template the pattern was compiled from.
+func (p *AssetPattern) String() string {
+	return p.template
+}
+
+// ExtractPlatform extracts OS and architecture from a file name matching the pattern.
+// A leading "*" (binary mode marker in sha256sum output) and any directory prefix are ignored.
+func (p *AssetPattern) ExtractPlatform(filename string) (*Platform, error) {
+	base := path.Base(strings.TrimPrefix(filename, "*"))
+
+	matches := p.re.FindStringSubmatch(base)
+	if matches == nil {
+		return nil, fmt.Errorf("filename does not match expected pattern %q: %s", p.template, filename)
+	}
+
+	return &Platform{
+		OS:   matches[p.re.SubexpIndex("os")],
+		Arch: matches[p.re.SubexpIndex("arch")],
+	}, nil
+}
+
+// expandPlaceholders replaces each {placeholder} in template with its value from vars.
+// Unknown placeholders are left untouched.
+func expandPlaceholders(template string, vars map[string]string) string {
+	return placeholderRegexp.ReplaceAllStringFunc(template, func(m string) string {
+		if v, ok := vars[m[1:len(m)-1]]; ok {
+			return v
+		}
+		return m
+	})
+}
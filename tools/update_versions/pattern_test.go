@@ -0,0 +1,149 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileAssetPattern(t *testing.T) {
+	tests := []struct {
+		name      string
+		toolName  string
+		template  string
+		wantError bool
+	}{
+		{
+			name:     "default pattern",
+			toolName: "golangci-lint",
+			template: DefaultAssetPattern,
+		},
+		{
+			name:     "pattern without name or version",
+			toolName: "",
+			template: "tool_{os}_{arch}.{ext}",
+		},
+		{
+			name:      "unknown placeholder",
+			toolName:  "golangci-lint",
+			template:  "{name}-{release}-{os}-{arch}.{ext}",
+			wantError: true,
+		},
+		{
+			name:      "missing arch placeholder",
+			toolName:  "golangci-lint",
+			template:  "{name}-{version}-{os}.{ext}",
+			wantError: true,
+		},
+		{
+			name:      "duplicate placeholder",
+			toolName:  "golangci-lint",
+			template:  "{name}-{os}-{arch}-{os}.{ext}",
+			wantError: true,
+		},
+		{
+			name:      "name placeholder without tool name",
+			toolName:  "",
+			template:  DefaultAssetPattern,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := CompileAssetPattern(tt.toolName, tt.template)
+			if tt.wantError {
+				assert.Error(t, err, "CompileAssetPattern() should return error")
+				return
+			}
+
+			require.NoError(t, err, "CompileAssetPattern() should not return error")
+			assert.Equal(t, tt.template, pattern.String(), "CompileAssetPattern() should preserve template")
+		})
+	}
+}
+
+func TestAssetPattern_ExtractPlatform(t *testing.T) {
+	tests := []struct {
+		name      string
+		toolName  string
+		template  string
+		filename  string
+		wantOS    string
+		wantArch  string
+		wantError bool
+	}{
+		{
+			name:     "prerelease version",
+			toolName: "golangci-lint",
+			template: DefaultAssetPattern,
+			filename: "golangci-lint-2.7.0-rc.1-linux-amd64.tar.gz",
+			wantOS:   "linux",
+			wantArch: "amd64",
+		},
+		{
+			name:     "hyphenated prerelease version",
+			toolName: "golangci-lint",
+			template: DefaultAssetPattern,
+			filename: "golangci-lint-2.7.0-beta-2-darwin-arm64.tar.gz",
+			wantOS:   "darwin",
+			wantArch: "arm64",
+		},
+		{
+			name:     "build metadata",
+			toolName: "golangci-lint",
+			template: DefaultAssetPattern,
+			filename: "golangci-lint-2.7.0+build.5-windows-amd64.zip",
+			wantOS:   "windows",
+			wantArch: "amd64",
+		},
+		{
+			name:     "binary mode marker and directory prefix",
+			toolName: "golangci-lint",
+			template: DefaultAssetPattern,
+			filename: "*dist/golangci-lint-2.6.1-linux-arm64.tar.gz",
+			wantOS:   "linux",
+			wantArch: "arm64",
+		},
+		{
+			name:     "custom tool layout",
+			toolName: "gofumpt",
+			template: "{name}_v{version}_{os}_{arch}.{ext}",
+			filename: "gofumpt_v0.7.0_linux_amd64.tar.gz",
+			wantOS:   "linux",
+			wantArch: "amd64",
+		},
+		{
+			name:      "different tool name",
+			toolName:  "golangci-lint",
+			template:  DefaultAssetPattern,
+			filename:  "gofumpt-0.7.0-linux-amd64.tar.gz",
+			wantError: true,
+		},
+		{
+			name:      "trailing garbage",
+			toolName:  "golangci-lint",
+			template:  DefaultAssetPattern,
+			filename:  "golangci-lint-2.6.1-linux-amd64.tar.gz.sig",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := CompileAssetPattern(tt.toolName, tt.template)
+			require.NoError(t, err, "CompileAssetPattern() should not return error")
+
+			platform, err := pattern.ExtractPlatform(tt.filename)
+			if tt.wantError {
+				assert.Error(t, err, "ExtractPlatform() should return error")
+				return
+			}
+
+			require.NoError(t, err, "ExtractPlatform() should not return error")
+			assert.Equal(t, tt.wantOS, platform.OS, "ExtractPlatform() should return correct OS")
+			assert.Equal(t, tt.wantArch, platform.Arch, "ExtractPlatform() should return correct Arch")
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// armVariantRegexp matches release architectures naming a GOARM level, e.g. armv6.
+var armVariantRegexp = regexp.MustCompile(`^armv(\d+)$`)
+
+// PlatformData is one os/arch pair published by at least one version, with the Bazel
+// constraints a toolchain for it is compatible with.
+type PlatformData struct {
+	// OS and Arch are the release names, e.g. "linux" and "armv6".
+	OS   string
+	Arch string
+	// GOARM is the ARM level of armv6/armv7 builds ("6", "7"), empty for other architectures.
+	GOARM string
+	// Constraints holds the @platforms//os and @platforms//cpu labels.
+	Constraints []string
+}
+
+// Name returns the platform's "<os>_<arch>" key.
+func (p PlatformData) Name() string {
+	return p.OS + "_" + p.Arch
+}
+
+// ComputePlatforms returns every platform of versions, sorted by OS then architecture.
+// Platforms without a known constraint mapping are logged and left out. When platforms
+// share their constraints (e.g. "arm" and "armv6"), Bazel could not tell their toolchains
+// apart, so only the first by architecture name is kept and the others are logged.
+func ComputePlatforms(versions []VersionData) []PlatformData {
+	seen := make(map[Platform]bool)
+	var platforms []PlatformData
+	for _, v := range versions {
+		for goos, archs := range v.ChecksumsByOS {
+			for arch := range archs {
+				if seen[Platform{OS: goos, Arch: arch}] {
+					continue
+				}
+				seen[Platform{OS: goos, Arch: arch}] = true
+
+				constraints, err := Constraints(goos, arch)
+				if err != nil {
+					log.Printf("Warning: %s/%s has no Bazel platform mapping, skipping: %v", goos, arch, err)
+					continue
+				}
+				platforms = append(platforms, PlatformData{
+					OS:          goos,
+					Arch:        arch,
+					GOARM:       GOARM(arch),
+					Constraints: constraints,
+				})
+			}
+		}
+	}
+
+	sort.Slice(platforms, func(i, j int) bool {
+		if platforms[i].OS != platforms[j].OS {
+			return platforms[i].OS < platforms[j].OS
+		}
+		return platforms[i].Arch < platforms[j].Arch
+	})
+
+	kept := make(map[string]PlatformData)
+	unique := platforms[:0]
+	for _, p := range platforms {
+		key := strings.Join(p.Constraints, ",")
+		if winner, ok := kept[key]; ok {
+			log.Printf("Warning: %s/%s shares its Bazel constraints with %s/%s, skipping", p.OS, p.Arch, winner.OS, winner.Arch)
+			continue
+		}
+		kept[key] = p
+		unique = append(unique, p)
+	}
+	return unique
+}
+
+// GOARM returns the ARM level a release architecture like "armv7" was built for, or ""
+// if the architecture does not name one.
+func GOARM(arch string) string {
+	if m := armVariantRegexp.FindStringSubmatch(arch); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputePlatforms(t *testing.T) {
+	versions := []VersionData{
+		{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{
+			"linux":  {"armv7": "a", "amd64": "b", "mips64le": "c"},
+			"darwin": {"arm64": "d"},
+			"plan9":  {"amd64": "e"},
+		}},
+		{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{
+			"linux":   {"armv6": "f", "amd64": "g"},
+			"illumos": {"amd64": "h"},
+		}},
+	}
+
+	assert.Equal(t, []PlatformData{
+		{OS: "darwin", Arch: "arm64", Constraints: []string{"@platforms//os:macos", "@platforms//cpu:aarch64"}},
+		{OS: "illumos", Arch: "amd64", Constraints: []string{"@platforms//os:illumos", "@platforms//cpu:x86_64"}},
+		{OS: "linux", Arch: "amd64", Constraints: []string{"@platforms//os:linux", "@platforms//cpu:x86_64"}},
+		{OS: "linux", Arch: "armv6", GOARM: "6", Constraints: []string{"@platforms//os:linux", "@platforms//cpu:arm"}},
+		{OS: "linux", Arch: "armv7", GOARM: "7", Constraints: []string{"@platforms//os:linux", "@platforms//cpu:armv7"}},
+	}, ComputePlatforms(versions), "ComputePlatforms() should map every platform of every version, skipping unknown ones")
+}
+
+func TestComputePlatforms_UniqueConstraints(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	versions := []VersionData{
+		{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{
+			"linux": {"amd64": "a", "arm": "b", "armv6": "c", "mips64": "d", "mips64le": "e", "ppc64": "f", "ppc64le": "g"},
+		}},
+	}
+
+	platforms := ComputePlatforms(versions)
+	seen := make(map[string]string)
+	for _, p := range platforms {
+		key := strings.Join(p.Constraints, ",")
+		if other, ok := seen[key]; ok {
+			t.Errorf("%s and %s share the constraints %s", other, p.Name(), key)
+		}
+		seen[key] = p.Name()
+	}
+
+	var names []string
+	for _, p := range platforms {
+		names = append(names, p.Name())
+	}
+	assert.Equal(t, []string{"linux_amd64", "linux_arm", "linux_mips64", "linux_ppc64", "linux_ppc64le"}, names,
+		"ComputePlatforms() should keep one platform of each set of colliding constraints")
+	assert.Contains(t, logs.String(), "linux/armv6 shares its Bazel constraints with linux/arm, skipping",
+		"ComputePlatforms() should log the platforms it drops for colliding constraints")
+	assert.Contains(t, logs.String(), "linux/mips64le has no Bazel platform mapping, skipping",
+		"ComputePlatforms() should log platforms without a mapping")
+}
+
+func TestComputePlatforms_CollisionWinnerIsDeterministic(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		versions := []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"armv6": "a", "armv5": "b"}}},
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{"linux": {"arm": "c"}}},
+		}
+		platforms := ComputePlatforms(versions)
+		require.Len(t, platforms, 1, "ComputePlatforms() should keep one of the colliding platforms")
+		assert.Equal(t, "linux_arm", platforms[0].Name(), "ComputePlatforms() should keep the first architecture by name")
+	}
+}
+
+func TestCPUConstraint_PPC64(t *testing.T) {
+	label, err := CPUConstraint("ppc64")
+	require.NoError(t, err, "CPUConstraint() should map ppc64")
+	assert.Equal(t, "@platforms//cpu:ppc64", label, "CPUConstraint() should map ppc64 to the 64-bit constraint")
+
+	_, err = CPUConstraint("mips64le")
+	assert.Error(t, err, "CPUConstraint() should have no mapping for mips64le")
+}
+
+func TestGOARM(t *testing.T) {
+	assert.Equal(t, "6", GOARM("armv6"), "GOARM() should read the level of armv6")
+	assert.Equal(t, "7", GOARM("armv7"), "GOARM() should read the level of armv7")
+	assert.Empty(t, GOARM("arm"), "GOARM() should be empty without a level")
+	assert.Empty(t, GOARM("arm64"), "GOARM() should be empty for arm64")
+
+	label, err := CPUConstraint("armv5")
+	require.NoError(t, err, "CPUConstraint() should accept other GOARM levels")
+	assert.Equal(t, "@platforms//cpu:arm", label, "CPUConstraint() should map armv5 to arm")
+}
+
+func TestGenerateStarlarkFile_Platforms(t *testing.T) {
+	data := &TemplateData{
+		DefaultVersion: "v2.6.1",
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"armv6": "a", "amd64": "b"}}},
+		},
+	}
+	data.Platforms = ComputePlatforms(data.Versions)
+	outputFile := filepath.Join(t.TempDir(), "versions.bzl")
+
+	_, err := GenerateStarlarkFile(data, outputFile)
+	require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output")
+	assert.Contains(t, string(content), `GOLANGCI_PLATFORMS = {
+    "linux_amd64": {
+        "os": "linux",
+        "arch": "amd64",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:x86_64",
+        ],
+    },
+    "linux_armv6": {
+        "os": "linux",
+        "arch": "armv6",
+        "goarm": "6",
+        "constraints": [
+            "@platforms//os:linux",
+            "@platforms//cpu:arm",
+        ],
+    },
+}`, "output should list the constraints of every platform")
+}
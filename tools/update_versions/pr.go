@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v62/github"
+)
+
+// PullRequestConfig configures publishing a run's changes as a pull request.
+type PullRequestConfig struct {
+	// Remote is the git remote the base branch is fetched from and the branch pushed to.
+	Remote string
+	// Base is the branch the pull request targets.
+	Base string
+	// Branch is the branch the changes are committed to. It is reset to the remote's Base
+	// on every run.
+	Branch string
+	// Repo is the "owner/name" repository of the pull request. Empty means derive it
+	// from the remote's URL.
+	Repo string
+}
+
+// PullRequestClient opens and updates pull requests through the GitHub API.
+type PullRequestClient struct {
+	client *github.Client
+}
+
+// NewPullRequestClient creates a client for the GitHub API at apiURL (empty for
+// api.github.com), authenticated with token, making requests through httpClient; nil means
+// a client with the default HTTPConfig.
+func NewPullRequestClient(httpClient *http.Client, apiURL, token string) (*PullRequestClient, error) {
+	client := github.NewClient(clientOrDefault(httpClient)).WithAuthToken(token)
+	if apiURL != "" {
+		base, err := url.Parse(strings.TrimRight(apiURL, "/") + "/")
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub API URL %q: %w", apiURL, err)
+		}
+		client.BaseURL = base
+	}
+	return &PullRequestClient{client: client}, nil
+}
+
+// CreateOrUpdate opens a pull request from branch into base, or updates the title and
+// body of the open one. It returns the pull request's URL.
+func (c *PullRequestClient) CreateOrUpdate(ctx context.Context, repo, base, branch, title, body string) (string, error) {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid repository %q: expected owner/name", repo)
+	}
+
+	existing, _, err := c.client.PullRequests.List(ctx, owner, name, &github.PullRequestListOptions{
+		State: "open",
+		Head:  owner + ":" + branch,
+		Base:  base,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	if len(existing) > 0 {
+		pr, _, err := c.client.PullRequests.Edit(ctx, owner, name, existing[0].GetNumber(), &github.PullRequest{
+			Title: github.String(title),
+			Body:  github.String(body),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to update pull request #%d: %w", existing[0].GetNumber(), err)
+		}
+		return pr.GetHTMLURL(), nil
+	}
+
+	pr, _, err := c.client.PullRequests.Create(ctx, owner, name, &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(branch),
+		Base:  github.String(base),
+		Body:  github.String(body),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create pull request: %w", err)
+	}
+	return pr.GetHTMLURL(), nil
+}
+
+// gitRepo runs git commands in a working tree.
+type gitRepo struct {
+	dir string
+	// indexFile replaces the repository's index when set, leaving the user's staged
+	// changes alone.
+	indexFile string
+}
+
+// run executes git with args and returns its trimmed output.
+func (g gitRepo) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.dir
+	if g.indexFile != "" {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+g.indexFile)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// hasStagedChanges reports whether the index differs from commit within paths.
+func (g gitRepo) hasStagedChanges(ctx context.Context, commit string, paths []string) (bool, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"diff-index", "--cached", "--quiet", commit, "--"}, paths...)...)
+	cmd.Dir = g.dir
+	if g.indexFile != "" {
+		cmd.Env = append(os.Environ(), "GIT_INDEX_FILE="+g.indexFile)
+	}
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("git diff-index --cached: %w", err)
+	}
+	return false, nil
+}
+
+// remoteRepoRegexp extracts owner/name from https, ssh and scp-style GitHub remote URLs.
+var remoteRepoRegexp = regexp.MustCompile(`[:/]([^/:]+/[^/]+?)(?:\.git)?/?$`)
+
+// repoFromRemoteURL derives "owner/name" from a git remote URL.
+func repoFromRemoteURL(remoteURL string) (string, error) {
+	m := remoteRepoRegexp.FindStringSubmatch(remoteURL)
+	if m == nil {
+		return "", fmt.Errorf("cannot derive owner/name from remote URL %q", remoteURL)
+	}
+	return m[1], nil
+}
+
+// pullRequestTitle names the new default version of each changed tool.
+func pullRequestTitle(changelogs []*Changelog) string {
+	var changed []*Changelog
+	for _, c := range changelogs {
+		if c.HasChanges() {
+			changed = append(changed, c)
+		}
+	}
+	if len(changed) == 1 {
+		return fmt.Sprintf("Update %s versions (default %s)", changed[0].Tool, changed[0].Default)
+	}
+
+	defaults := make([]string, 0, len(changed))
+	for _, c := range changed {
+		defaults = append(defaults, c.Tool+" "+c.Default)
+	}
+	return fmt.Sprintf("Update tool versions (%s)", strings.Join(defaults, ", "))
+}
+
+// OpenPullRequest commits the outputs and cache files of the last Run on top of the remote's
+// cfg.Base, force-pushes the commit to cfg.Branch and opens or updates a pull request with
+// the changelog as its body. The commit is built in a temporary index, so the checkout, its
+// index and its other changes are left as they are. It does nothing when the run changed no
+// version data.
+func (r *Runner) OpenPullRequest(ctx context.Context, cfg PullRequestConfig, prs *PullRequestClient) error {
+	hasChanges := false
+	for _, c := range r.changelogs {
+		hasChanges = hasChanges || c.HasChanges()
+	}
+	if !hasChanges {
+		log.Println("No version changes; not opening a pull request")
+		return nil
+	}
+
+	git := gitRepo{dir: r.config.WorkspaceRoot}
+
+	repo := cfg.Repo
+	if repo == "" {
+		// The configured URL names the repository even when insteadOf rewrites it for fetches
+		remoteURL, err := git.run(ctx, "config", "--get", "remote."+cfg.Remote+".url")
+		if err != nil {
+			return err
+		}
+		if repo, err = repoFromRemoteURL(remoteURL); err != nil {
+			return err
+		}
+	}
+
+	if _, err := git.run(ctx, "fetch", "--quiet", cfg.Remote, cfg.Base); err != nil {
+		return err
+	}
+	base, err := git.run(ctx, "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return err
+	}
+
+	// Stage the generated files on top of the base in an index of our own
+	tempDir, err := os.MkdirTemp("", "update_versions-pr-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	git.indexFile = filepath.Join(tempDir, "index")
+
+	paths := r.publishedPaths()
+	if _, err := git.run(ctx, "read-tree", base); err != nil {
+		return err
+	}
+	if _, err := git.run(ctx, append([]string{"add", "--"}, paths...)...); err != nil {
+		return err
+	}
+	staged, err := git.hasStagedChanges(ctx, base, paths)
+	if err != nil {
+		return err
+	}
+	if !staged {
+		log.Println("Generated files match the base branch; not opening a pull request")
+		return nil
+	}
+
+	title := pullRequestTitle(r.changelogs)
+	tree, err := git.run(ctx, "write-tree")
+	if err != nil {
+		return err
+	}
+	commit, err := git.run(ctx, "commit-tree", tree, "-p", base, "-m", title)
+	if err != nil {
+		return err
+	}
+	if _, err := git.run(ctx, "push", "--force", cfg.Remote, commit+":refs/heads/"+cfg.Branch); err != nil {
+		return err
+	}
+	log.Printf("Pushed %s to %s", cfg.Branch, cfg.Remote)
+
+	prURL, err := prs.CreateOrUpdate(ctx, repo, cfg.Base, cfg.Branch, title, RenderChangelogs(r.changelogs))
+	if err != nil {
+		return err
+	}
+	log.Printf("Pull request: %s", prURL)
+
+	return nil
+}
+
+// publishedPaths returns the workspace-relative outputs and cache directories of all tools
+// as git pathspecs. The release snapshot in each cache directory holds raw listing responses
+// and is excluded. Paths outside the workspace are skipped.
+func (r *Runner) publishedPaths() []string {
+	var paths []string
+	for _, tool := range r.tools() {
+		cacheDir := filepath.Join(r.resolvePath(r.config.CacheDir), tool.CacheSubdir)
+		if rel, ok := r.workspaceRelative(cacheDir); ok {
+			paths = append(paths, rel, ":(exclude)"+filepath.ToSlash(filepath.Join(rel, ReleaseSnapshotFile)))
+		}
+		for _, out := range tool.AllOutputs() {
+			if rel, ok := r.workspaceRelative(r.resolvePath(out.Path)); ok {
+				paths = append(paths, rel)
+			}
+		}
+	}
+	return paths
+}
+
+// workspaceRelative returns path relative to the workspace root, or false if it lies outside.
+func (r *Runner) workspaceRelative(path string) (string, bool) {
+	rel, err := filepath.Rel(r.config.WorkspaceRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gitCmd runs git in dir and returns its trimmed output, failing the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, "git %s failed: %s", strings.Join(args, " "), out)
+	return strings.TrimSpace(string(out))
+}
+
+// newGitWorkspace creates a clone of a local bare repository with one commit on main.
+// It returns the workspace and the bare repository paths.
+func newGitWorkspace(t *testing.T) (string, string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+
+	tempDir := t.TempDir()
+	remote := filepath.Join(tempDir, "remote.git")
+	workspace := filepath.Join(tempDir, "workspace")
+
+	gitCmd(t, tempDir, "init", "--quiet", "--bare", "--initial-branch=main", remote)
+	gitCmd(t, tempDir, "clone", "--quiet", remote, workspace)
+	gitCmd(t, workspace, "checkout", "--quiet", "-b", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "README.md"), []byte("test\n"), 0644), "Failed to write file")
+	gitCmd(t, workspace, "add", "README.md")
+	gitCmd(t, workspace, "commit", "--quiet", "-m", "Initial commit")
+	gitCmd(t, workspace, "push", "--quiet", "origin", "main")
+
+	return workspace, remote
+}
+
+// pullRequestAPI is an httptest stand-in for the GitHub pull request endpoints.
+type pullRequestAPI struct {
+	existing []map[string]any
+	listHead string
+	created  map[string]string
+	edited   map[string]string
+}
+
+func (a *pullRequestAPI) serve(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		decode := func() map[string]string {
+			body, _ := io.ReadAll(r.Body)
+			var fields map[string]string
+			_ = json.Unmarshal(body, &fields)
+			return fields
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/rules/pulls":
+			a.listHead = r.URL.Query().Get("head")
+			_ = json.NewEncoder(w).Encode(a.existing)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/rules/pulls":
+			a.created = decode()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number": 1, "html_url": "https://github.example.com/acme/rules/pull/1"}`))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/acme/rules/pulls/7":
+			a.edited = decode()
+			_, _ = w.Write([]byte(`{"number": 7, "html_url": "https://github.example.com/acme/rules/pull/7"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// runUpdate runs the updater in workspace against a mock release with the given checksum line.
+func runUpdate(t *testing.T, workspace, checksums string) *Runner {
+	t.Helper()
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte(checksums),
+	)
+
+	runner := NewRunner(Config{
+		Count:         1,
+		CacheDir:      "cache",
+		OutputFile:    "private/versions.bzl",
+		WorkspaceRoot: workspace,
+		OmitTimestamp: true,
+	}, mock)
+	require.NoError(t, runner.Run(context.Background()), "Runner.Run() should succeed")
+
+	return runner
+}
+
+func TestRunner_OpenPullRequest_Creates(t *testing.T) {
+	workspace, remote := newGitWorkspace(t)
+	api := &pullRequestAPI{}
+	server := api.serve(t)
+
+	runner := runUpdate(t, workspace, "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n")
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "cache", ReleaseSnapshotFile), []byte("{}\n"), 0644), "Failed to write file")
+
+	prs, err := NewPullRequestClient(nil, server.URL, "secret")
+	require.NoError(t, err, "NewPullRequestClient() should succeed")
+
+	err = runner.OpenPullRequest(context.Background(), PullRequestConfig{Remote: "origin", Base: "main", Branch: "update-versions", Repo: "acme/rules"}, prs)
+	require.NoError(t, err, "OpenPullRequest() should succeed")
+
+	assert.Equal(t, "Update golangci-lint versions (default v2.6.1)",
+		gitCmd(t, remote, "log", "-1", "--format=%s", "update-versions"), "OpenPullRequest() should push a commit naming the default version")
+	files := gitCmd(t, remote, "ls-tree", "-r", "--name-only", "update-versions")
+	assert.Contains(t, files, "private/versions.bzl", "commit should include the generated file")
+	assert.Contains(t, files, "cache/v2.6.1.txt", "commit should include new cache files")
+	assert.NotContains(t, files, ReleaseSnapshotFile, "commit should leave out the release snapshot")
+
+	assert.Equal(t, "acme:update-versions", api.listHead, "OpenPullRequest() should look for an open pull request from the branch")
+	require.NotNil(t, api.created, "OpenPullRequest() should create a pull request")
+	assert.Equal(t, "update-versions", api.created["head"], "pull request should be opened from the branch")
+	assert.Equal(t, "main", api.created["base"], "pull request should target the base branch")
+	assert.Contains(t, api.created["body"], "### Added versions", "pull request body should be the changelog")
+}
+
+func TestRunner_OpenPullRequest_OnlyPublishesGeneratedFiles(t *testing.T) {
+	workspace, remote := newGitWorkspace(t)
+	api := &pullRequestAPI{}
+	server := api.serve(t)
+	prs, err := NewPullRequestClient(nil, server.URL, "secret")
+	require.NoError(t, err, "NewPullRequestClient() should succeed")
+
+	// A local commit ahead of the base and an unrelated staged file must stay out of the pull request
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "local.txt"), []byte("local\n"), 0644), "Failed to write file")
+	gitCmd(t, workspace, "add", "local.txt")
+	gitCmd(t, workspace, "commit", "--quiet", "-m", "Local work")
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "staged.txt"), []byte("staged\n"), 0644), "Failed to write file")
+	gitCmd(t, workspace, "add", "staged.txt")
+
+	runner := runUpdate(t, workspace, "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n")
+	err = runner.OpenPullRequest(context.Background(), PullRequestConfig{Remote: "origin", Base: "main", Branch: "update-versions", Repo: "acme/rules"}, prs)
+	require.NoError(t, err, "OpenPullRequest() should succeed")
+
+	assert.Equal(t, gitCmd(t, remote, "rev-parse", "main"), gitCmd(t, remote, "rev-parse", "update-versions^"),
+		"the branch should start from the remote base")
+	files := gitCmd(t, remote, "ls-tree", "-r", "--name-only", "update-versions")
+	assert.Contains(t, files, "private/versions.bzl", "commit should include the generated file")
+	assert.NotContains(t, files, "local.txt", "local commits should not be published")
+	assert.NotContains(t, files, "staged.txt", "unrelated staged files should not be committed")
+}
+
+func TestRunner_OpenPullRequest_LeavesCheckoutAlone(t *testing.T) {
+	workspace, remote := newGitWorkspace(t)
+	api := &pullRequestAPI{}
+	server := api.serve(t)
+	prs, err := NewPullRequestClient(nil, server.URL, "secret")
+	require.NoError(t, err, "NewPullRequestClient() should succeed")
+
+	// The generated file differs between the base and HEAD, and the checkout has other edits
+	generated := filepath.Join(workspace, "private", "versions.bzl")
+	require.NoError(t, os.MkdirAll(filepath.Dir(generated), 0755), "Failed to create directory")
+	require.NoError(t, os.WriteFile(generated, []byte("# base\n"), 0644), "Failed to write file")
+	gitCmd(t, workspace, "add", "private/versions.bzl")
+	gitCmd(t, workspace, "commit", "--quiet", "-m", "Base versions")
+	gitCmd(t, workspace, "push", "--quiet", "origin", "main")
+	require.NoError(t, os.WriteFile(generated, []byte("# local\n"), 0644), "Failed to write file")
+	gitCmd(t, workspace, "commit", "--quiet", "-am", "Local versions")
+	head := gitCmd(t, workspace, "rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "staged.txt"), []byte("staged\n"), 0644), "Failed to write file")
+	gitCmd(t, workspace, "add", "staged.txt")
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "README.md"), []byte("edited\n"), 0644), "Failed to write file")
+
+	runner := runUpdate(t, workspace, "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n")
+	err = runner.OpenPullRequest(context.Background(), PullRequestConfig{Remote: "origin", Base: "main", Branch: "update-versions", Repo: "acme/rules"}, prs)
+	require.NoError(t, err, "OpenPullRequest() should succeed")
+
+	assert.Equal(t, gitCmd(t, remote, "rev-parse", "main"), gitCmd(t, remote, "rev-parse", "update-versions^"),
+		"the branch should start from the remote base")
+	want, err := os.ReadFile(generated)
+	require.NoError(t, err, "Failed to read generated file")
+	assert.Equal(t, string(want), gitCmd(t, remote, "show", "update-versions:private/versions.bzl")+"\n",
+		"the branch should carry the regenerated file")
+
+	assert.Equal(t, "main", gitCmd(t, workspace, "rev-parse", "--abbrev-ref", "HEAD"), "the checkout should stay on its branch")
+	assert.Equal(t, head, gitCmd(t, workspace, "rev-parse", "HEAD"), "HEAD should not move")
+	assert.Equal(t, "staged.txt", gitCmd(t, workspace, "diff", "--cached", "--name-only"), "the index should be untouched")
+	readme, err := os.ReadFile(filepath.Join(workspace, "README.md"))
+	require.NoError(t, err, "Failed to read file")
+	assert.Equal(t, "edited\n", string(readme), "unrelated edits should stay in the working tree")
+}
+
+func TestRunner_OpenPullRequest_UpdatesExisting(t *testing.T) {
+	workspace, remote := newGitWorkspace(t)
+	gitCmd(t, workspace, "remote", "set-url", "origin", "git@github.com:acme/rules.git")
+	gitCmd(t, workspace, "config", "url."+remote+".insteadOf", "git@github.com:acme/rules.git")
+
+	api := &pullRequestAPI{existing: []map[string]any{{"number": 7}}}
+	server := api.serve(t)
+	prs, err := NewPullRequestClient(nil, server.URL, "secret")
+	require.NoError(t, err, "NewPullRequestClient() should succeed")
+
+	runner := runUpdate(t, workspace, "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n")
+	err = runner.OpenPullRequest(context.Background(), PullRequestConfig{Remote: "origin", Base: "main", Branch: "update-versions"}, prs)
+	require.NoError(t, err, "OpenPullRequest() should succeed")
+
+	assert.Nil(t, api.created, "OpenPullRequest() should not open a second pull request")
+	require.NotNil(t, api.edited, "OpenPullRequest() should update the open pull request")
+	assert.Contains(t, api.edited["body"], "v2.6.1", "updated body should be the changelog")
+	assert.Equal(t, "Update golangci-lint versions (default v2.6.1)", api.edited["title"], "updated title should name the default version")
+	assert.NotEmpty(t, gitCmd(t, remote, "rev-parse", "update-versions"), "OpenPullRequest() should push the branch")
+}
+
+func TestRunner_OpenPullRequest_NoChanges(t *testing.T) {
+	workspace, remote := newGitWorkspace(t)
+	api := &pullRequestAPI{}
+	server := api.serve(t)
+	prs, err := NewPullRequestClient(nil, server.URL, "secret")
+	require.NoError(t, err, "NewPullRequestClient() should succeed")
+
+	checksums := "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"
+	runUpdate(t, workspace, checksums)
+	gitCmd(t, workspace, "add", "-A")
+	gitCmd(t, workspace, "commit", "--quiet", "-m", "Existing versions")
+
+	runner := runUpdate(t, workspace, checksums)
+	err = runner.OpenPullRequest(context.Background(), PullRequestConfig{Remote: "origin", Base: "main", Branch: "update-versions", Repo: "acme/rules"}, prs)
+	require.NoError(t, err, "OpenPullRequest() should succeed")
+
+	assert.Nil(t, api.created, "OpenPullRequest() should not open a pull request without changes")
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "update-versions")
+	cmd.Dir = remote
+	assert.Error(t, cmd.Run(), "OpenPullRequest() should not push a branch without changes")
+}
+
+func TestRepoFromRemoteURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/jmgilman/bazel_rules_go.git", "jmgilman/bazel_rules_go"},
+		{"https://github.com/jmgilman/bazel_rules_go", "jmgilman/bazel_rules_go"},
+		{"git@github.com:jmgilman/bazel_rules_go.git", "jmgilman/bazel_rules_go"},
+		{"ssh://git@github.example.com/acme/rules.git", "acme/rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := repoFromRemoteURL(tt.url)
+			require.NoError(t, err, "repoFromRemoteURL() should succeed")
+			assert.Equal(t, tt.want, got, "repoFromRemoteURL() should extract owner/name")
+		})
+	}
+
+	_, err := repoFromRemoteURL("rules")
+	assert.Error(t, err, "repoFromRemoteURL() should reject URLs without owner")
+}
+
+func TestPullRequestTitle(t *testing.T) {
+	changelogs := []*Changelog{
+		{Tool: "golangci-lint", PreviousDefault: "v2.6.0", Default: "v2.6.1"},
+		{Tool: "gofumpt", PreviousDefault: "v0.7.0", Default: "v0.7.0"},
+		{Tool: "buf", PreviousDefault: "v1.47.0", Default: "v1.47.2"},
+	}
+
+	assert.Equal(t, "Update tool versions (golangci-lint v2.6.1, buf v1.47.2)", pullRequestTitle(changelogs),
+		"pullRequestTitle() should name the default of each changed tool")
+	assert.Equal(t, "Update golangci-lint versions (default v2.6.1)", pullRequestTitle(changelogs[:2]),
+		"pullRequestTitle() should name a single changed tool")
+}
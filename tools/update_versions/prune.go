@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// cacheFileExtensions are the per-version files kept in a tool's cache directory: the
+// checksum file, the recorded Go version and the recorded license.
+var cacheFileExtensions = []string{".txt", ".goversion", ".license"}
+
+// PruneOptions controls which cache entries Prune removes.
+type PruneOptions struct {
+	// Keep lists tags to keep in addition to the versions in the generated data.
+	Keep []string
+	// KeepDays keeps entries downloaded less than this many days ago, per the cache index.
+	// Entries without a download time count as expired.
+	KeepDays int
+	// DryRun lists the entries without removing them.
+	DryRun bool
+	// Now is the time KeepDays is measured from.
+	Now time.Time
+}
+
+// Prune removes the cache files of versions that are neither in the tool's generated data
+// nor in opts.Keep, along with their cache index entries, and returns their paths in
+// sorted order. It refuses to run if the tool has no generated data to compare against.
+func (r *Runner) Prune(tool Tool, opts PruneOptions) ([]string, error) {
+	data, err := LoadPreviousData(tool, r.resolvePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version data: %w", err)
+	}
+	if data == nil || len(data.Versions) == 0 {
+		return nil, fmt.Errorf("no generated version data for %s; refusing to prune its whole cache", tool.Name)
+	}
+
+	referenced := make(map[string]bool)
+	for _, v := range data.Versions {
+		referenced[v.Tag] = true
+	}
+	for _, tag := range opts.Keep {
+		referenced[tag] = true
+	}
+
+	unlock, err := lockDir(r.resolvePath(r.config.CacheDir))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	cacheDir := filepath.Join(r.resolvePath(r.config.CacheDir), tool.CacheSubdir)
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	index, err := LoadCacheIndex(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		tag, ok := cachedTag(entry.Name())
+		if !ok || referenced[tag] || index.fetchedWithin(tag+".txt", opts.Now, opts.KeepDays) {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		paths = append(paths, filepath.Join(cacheDir, name))
+	}
+	if opts.DryRun || len(names) == 0 {
+		return paths, nil
+	}
+
+	for i, name := range names {
+		if err := os.Remove(paths[i]); err != nil {
+			return paths[:i], fmt.Errorf("failed to remove %s: %w", name, err)
+		}
+		delete(index.Entries, name)
+	}
+	if err := index.Save(); err != nil {
+		return paths, err
+	}
+	return paths, nil
+}
+
+// cachedTag returns the tag of a per-version cache file name, e.g. v2.6.1 for v2.6.1.txt.
+func cachedTag(name string) (string, bool) {
+	for _, ext := range cacheFileExtensions {
+		if tag, ok := strings.CutSuffix(name, ext); ok && tag != "" {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+// fetchedWithin reports whether the named file was downloaded less than days before now.
+func (i *CacheIndex) fetchedWithin(name string, now time.Time, days int) bool {
+	if days <= 0 {
+		return false
+	}
+	fetched, err := time.Parse(time.RFC3339, i.Entries[name].FetchedAt)
+	if err != nil {
+		return false
+	}
+	return now.Sub(fetched) < time.Duration(days)*24*time.Hour
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPruneCache generates versions.bzl listing v2.6.1 and fills the cache with files of
+// v2.6.1, v2.6.0 (downloaded two days before now) and v2.5.0 (never indexed).
+func setupPruneCache(t *testing.T, now time.Time) (*Runner, Tool, string) {
+	t.Helper()
+
+	workspace := t.TempDir()
+	cacheDir := filepath.Join(workspace, "cache")
+	require.NoError(t, os.MkdirAll(cacheDir, 0755))
+
+	data := &TemplateData{
+		DefaultVersion: "v2.6.1",
+		Versions:       []VersionData{{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "aaa"}}}},
+	}
+	_, err := GenerateStarlarkFile(data, filepath.Join(workspace, "versions.bzl"))
+	require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+
+	for _, name := range []string{"v2.6.1.txt", "v2.6.1.goversion", "v2.6.0.txt", "v2.6.0.license", "v2.5.0.txt", "notes.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(cacheDir, name), []byte(name), 0644))
+	}
+	index, err := LoadCacheIndex(cacheDir)
+	require.NoError(t, err)
+	index.Entries["v2.6.1.txt"] = CacheEntry{SHA256: "a", Origin: OriginCache}
+	index.Entries["v2.6.0.txt"] = CacheEntry{SHA256: "b", Origin: OriginNetwork, FetchedAt: now.Add(-48 * time.Hour).Format(time.RFC3339)}
+	require.NoError(t, index.Save())
+
+	tool := DefaultTool()
+	tool.OutputFile = "versions.bzl"
+	return NewRunner(Config{CacheDir: "cache", WorkspaceRoot: workspace}, nil), tool, cacheDir
+}
+
+func TestRunner_Prune(t *testing.T) {
+	now := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("dry run lists unreferenced entries", func(t *testing.T) {
+		runner, tool, cacheDir := setupPruneCache(t, now)
+
+		paths, err := runner.Prune(tool, PruneOptions{DryRun: true, Now: now})
+		require.NoError(t, err, "Prune() should succeed")
+		assert.Equal(t, []string{
+			filepath.Join(cacheDir, "v2.5.0.txt"),
+			filepath.Join(cacheDir, "v2.6.0.license"),
+			filepath.Join(cacheDir, "v2.6.0.txt"),
+		}, paths, "Prune() should list the files of unreferenced versions")
+		assert.FileExists(t, filepath.Join(cacheDir, "v2.5.0.txt"), "a dry run should not remove files")
+	})
+
+	t.Run("removes entries and their index records", func(t *testing.T) {
+		runner, tool, cacheDir := setupPruneCache(t, now)
+
+		paths, err := runner.Prune(tool, PruneOptions{Keep: []string{"v2.5.0"}, Now: now})
+		require.NoError(t, err, "Prune() should succeed")
+		assert.Len(t, paths, 2, "Prune() should remove v2.6.0 only")
+		assert.NoFileExists(t, filepath.Join(cacheDir, "v2.6.0.txt"), "Prune() should remove the checksum file")
+		assert.NoFileExists(t, filepath.Join(cacheDir, "v2.6.0.license"), "Prune() should remove the recorded license")
+		assert.FileExists(t, filepath.Join(cacheDir, "v2.5.0.txt"), "Prune() should keep pinned tags")
+		assert.FileExists(t, filepath.Join(cacheDir, "v2.6.1.goversion"), "Prune() should keep referenced versions")
+		assert.FileExists(t, filepath.Join(cacheDir, "notes.md"), "Prune() should ignore unrelated files")
+
+		index, err := LoadCacheIndex(cacheDir)
+		require.NoError(t, err)
+		assert.NotContains(t, index.Entries, "v2.6.0.txt", "Prune() should drop the index entry")
+		assert.Contains(t, index.Entries, "v2.6.1.txt", "Prune() should keep referenced index entries")
+	})
+
+	t.Run("keeps recent downloads", func(t *testing.T) {
+		runner, tool, cacheDir := setupPruneCache(t, now)
+
+		paths, err := runner.Prune(tool, PruneOptions{KeepDays: 3, DryRun: true, Now: now})
+		require.NoError(t, err, "Prune() should succeed")
+		assert.Equal(t, []string{filepath.Join(cacheDir, "v2.5.0.txt")}, paths,
+			"Prune() should keep entries within the grace period and expire those without a download time")
+	})
+
+	t.Run("refuses without generated data", func(t *testing.T) {
+		runner, tool, _ := setupPruneCache(t, now)
+		tool.OutputFile = "missing.bzl"
+
+		_, err := runner.Prune(tool, PruneOptions{Now: now})
+		assert.Error(t, err, "Prune() should not empty the cache without version data")
+	})
+}
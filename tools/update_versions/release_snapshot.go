@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// ReleaseSnapshotFile is the name of the release listing snapshot kept in each tool's
+// cache directory.
+const ReleaseSnapshotFile = "releases.json"
+
+// ErrOffline is returned for requests that --offline mode cannot serve from the cache.
+var ErrOffline = errors.New("not available offline")
+
+// ReleaseSnapshot holds the last release listing responses of a tool by request URL, with
+// the validators used to revalidate them. An unchanged listing is answered with 304 Not
+// Modified, which GitHub does not count against the rate limit; in offline mode the
+// snapshot is the only source of listings.
+type ReleaseSnapshot struct {
+	Responses map[string]SnapshotResponse `json:"responses"`
+
+	path    string
+	offline bool
+	changed bool
+}
+
+// SnapshotResponse is one cached release listing response.
+type SnapshotResponse struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         string `json:"body"`
+}
+
+// LoadReleaseSnapshot reads the release snapshot of a cache directory, or returns an empty
+// one if there is none. An offline snapshot never lets requests through to the network.
+func LoadReleaseSnapshot(dir string, offline bool) (*ReleaseSnapshot, error) {
+	snapshot := &ReleaseSnapshot{
+		Responses: make(map[string]SnapshotResponse),
+		path:      filepath.Join(dir, ReleaseSnapshotFile),
+		offline:   offline,
+	}
+
+	data, err := os.ReadFile(snapshot.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snapshot, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read release snapshot: %w", err)
+	}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse release snapshot %s: %w", snapshot.path, err)
+	}
+	if snapshot.Responses == nil {
+		snapshot.Responses = make(map[string]SnapshotResponse)
+	}
+	return snapshot, nil
+}
+
+// Save writes the snapshot back to its cache directory if a listing changed.
+func (s *ReleaseSnapshot) Save() error {
+	if !s.changed {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode release snapshot: %w", err)
+	}
+	if _, err := writeFileAtomic(s.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write release snapshot: %w", err)
+	}
+	s.changed = false
+	return nil
+}
+
+// snapshotKey is the context key of the snapshot a release listing request goes through.
+type snapshotKey struct{}
+
+// withReleaseSnapshot returns ctx with requests made under it served through snapshot.
+// Only release listings are made under such a context; asset downloads are not cached.
+func withReleaseSnapshot(ctx context.Context, snapshot *ReleaseSnapshot) context.Context {
+	return context.WithValue(ctx, snapshotKey{}, snapshot)
+}
+
+// snapshotTransport makes GET requests carrying a release snapshot conditional on the
+// cached response, and answers 304 Not Modified with it.
+type snapshotTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *snapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	snapshot, _ := req.Context().Value(snapshotKey{}).(*ReleaseSnapshot)
+	if snapshot == nil || req.Method != http.MethodGet {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	cached, ok := snapshot.Responses[key]
+	if snapshot.offline {
+		if !ok {
+			return nil, fmt.Errorf("%s is not in the release snapshot: %w", key, ErrOffline)
+		}
+		return cached.response(req, http.Header{}), nil
+	}
+
+	if ok {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		_ = resp.Body.Close()
+		log.Printf("Release list unchanged, using the cached listing")
+		return cached.response(req, resp.Header), nil
+	case resp.StatusCode == http.StatusOK:
+		body, err := readLimited(resp.Body, maxListingSize)
+		_ = resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		snapshot.Responses[key] = SnapshotResponse{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Body:         string(body),
+		}
+		snapshot.changed = true
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return resp, nil
+}
+
+// response returns the cached listing as a 200 response to req, with the given headers
+// (e.g. rate limit headers of the 304 response it replaces).
+func (r SnapshotResponse) response(req *http.Request, header http.Header) *http.Response {
+	header = header.Clone()
+	header.Set("Content-Length", strconv.Itoa(len(r.Body)))
+	if r.ETag != "" {
+		header.Set("ETag", r.ETag)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewBufferString(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// offlineSource is a release source that cannot download assets, for --offline runs.
+// Listings still reach the wrapped source, which serves them from the release snapshot.
+type offlineSource struct {
+	ReleaseSource
+}
+
+// DownloadAsset returns ErrOffline: every file an offline run needs must be cached.
+func (offlineSource) DownloadAsset(_ context.Context, url string) ([]byte, error) {
+	return nil, fmt.Errorf("%s is not cached: %w", url, ErrOffline)
+}
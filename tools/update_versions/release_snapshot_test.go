@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newETagServer serves a release listing with an ETag, answering matching conditional
+// requests with 304 Not Modified. It counts full responses in served.
+func newETagServer(t *testing.T, path, body string, served *int) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		*served++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestReleaseSnapshot_ConditionalRequests(t *testing.T) {
+	served := 0
+	server := newETagServer(t, "/api/v3/repos/tools/lint/releases", `[{"tag_name": "v1.1.0"}, {"tag_name": "v1.0.0"}]`, &served)
+	client, err := NewGitHubEnterpriseClient(nil, server.URL)
+	require.NoError(t, err, "NewGitHubEnterpriseClient() should succeed")
+	want := []Release{{TagName: "v1.1.0"}, {TagName: "v1.0.0"}}
+	dir := t.TempDir()
+
+	list := func(offline bool) ([]Release, error) {
+		snapshot, err := LoadReleaseSnapshot(dir, offline)
+		require.NoError(t, err, "LoadReleaseSnapshot() should succeed")
+		releases, err := client.GetLatestReleases(withReleaseSnapshot(context.Background(), snapshot), "tools/lint", 10)
+		require.NoError(t, snapshot.Save(), "Save() should succeed")
+		return releases, err
+	}
+
+	releases, err := list(false)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	assert.Equal(t, want, releases, "GetLatestReleases() should return the listing")
+	assert.FileExists(t, filepath.Join(dir, ReleaseSnapshotFile), "the listing should be snapshotted")
+
+	releases, err = list(false)
+	require.NoError(t, err, "GetLatestReleases() should accept a 304 response")
+	assert.Equal(t, want, releases, "an unchanged listing should be served from the snapshot")
+	assert.Equal(t, 1, served, "an unchanged listing should not be downloaded again")
+
+	server.Close()
+	releases, err = list(true)
+	require.NoError(t, err, "GetLatestReleases() should succeed offline")
+	assert.Equal(t, want, releases, "an offline listing should come from the snapshot")
+
+	snapshot, err := LoadReleaseSnapshot(dir, true)
+	require.NoError(t, err, "LoadReleaseSnapshot() should succeed")
+	_, err = client.GetLatestReleases(withReleaseSnapshot(context.Background(), snapshot), "tools/lint", 5)
+	assert.ErrorIs(t, err, ErrOffline, "an offline listing not in the snapshot should fail")
+}
+
+func TestRunner_Run_Offline(t *testing.T) {
+	checksums := "aaa1111111111111111111111111111111111111111111111111111111111111  golangci-lint-2.6.1-linux-amd64.tar.gz\n"
+	server := newReleaseServer(t, map[string]string{
+		"/index.json":      `{"releases": [{"tag": "v2.6.1", "checksum_url": "v2.6.1/sums.txt"}]}`,
+		"/v2.6.1/sums.txt": checksums,
+	}, nil)
+
+	tempDir := t.TempDir()
+	tool := Tool{
+		Name:       "golangci-lint",
+		Repo:       "golangci/golangci-lint",
+		OutputFile: "versions.bzl",
+		VarPrefix:  "GOLANGCI",
+		Source:     &SourceConfig{Type: SourceHTTPIndex, URL: server.URL + "/index.json"},
+	}
+	require.NoError(t, tool.Validate(), "Tool.Validate() should succeed")
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{tool},
+		OmitTimestamp: true,
+	}
+	require.NoError(t, NewRunner(config, NewMockGitHubClient()).Run(context.Background()), "Runner.Run() should succeed online")
+	online, err := os.ReadFile(filepath.Join(tempDir, "versions.bzl"))
+	require.NoError(t, err, "Failed to read output file")
+
+	server.Close()
+	config.Offline = true
+	require.NoError(t, NewRunner(config, NewMockGitHubClient()).Run(context.Background()), "Runner.Run() should succeed offline")
+	offline, err := os.ReadFile(filepath.Join(tempDir, "versions.bzl"))
+	require.NoError(t, err, "Failed to read output file")
+	assert.Equal(t, string(online), string(offline), "an offline run should reproduce the online output")
+
+	// Checksum files missing from the cache cannot be downloaded offline
+	require.NoError(t, os.Remove(filepath.Join(tempDir, "cache", tool.CacheSubdir, "v2.6.1.txt")))
+	err = NewRunner(config, NewMockGitHubClient()).Run(context.Background())
+	assert.Error(t, err, "Runner.Run() should fail offline without cached checksums")
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 )
@@ -14,98 +17,274 @@ type Config struct {
 	CacheDir      string
 	OutputFile    string
 	WorkspaceRoot string
+	// Tools lists the tools to maintain. If empty, DefaultTool is used with OutputFile.
+	Tools []Tool
+	// RecordGoVersions downloads each release's linux/amd64 archive to record the Go
+	// version its binary was built with.
+	RecordGoVersions bool
+	// RecordLicenses downloads each release's linux/amd64 archive to identify the license
+	// it ships.
+	RecordLicenses bool
+	// OmitTimestamp leaves the "Generated at" line out of generated files.
+	OmitTimestamp bool
+	// HTTPClient makes the requests of release sources the runner creates for tools with
+	// their own SourceConfig; nil means a client with the default HTTPConfig.
+	HTTPClient *http.Client
+	// Offline serves release listings from each tool's release snapshot and checksum
+	// files from the cache, without network access.
+	Offline bool
+	// ChangelogWriter, ChangelogFile and StepSummaryFile receive the Markdown summary
+	// of the run's changes; each is skipped when unset.
+	ChangelogWriter io.Writer
+	ChangelogFile   string
+	StepSummaryFile string
 }
 
+// releaseWindow is the number of releases listed to find the newest release of each major
+// line, GitHub's largest page size.
+const releaseWindow = 100
+
 // Runner orchestrates the version update workflow.
 type Runner struct {
-	config Config
-	client GitHubAPI
+	config     Config
+	client     ReleaseSource
+	changelogs []*Changelog
 }
 
-// NewRunner creates a new Runner with the given configuration and GitHub client.
-func NewRunner(config Config, client GitHubAPI) *Runner {
+// NewRunner creates a new Runner with the given configuration and default release source.
+// Tools with their own SourceConfig use a source created from it instead.
+func NewRunner(config Config, client ReleaseSource) *Runner {
 	return &Runner{
 		config: config,
 		client: client,
 	}
 }
 
-// Run executes the version update workflow.
+// Run executes the version update workflow for every configured tool.
+// A failing tool does not stop the others; all errors are returned together.
 func (r *Runner) Run(ctx context.Context) error {
-	log.Printf("golangci-lint version updater starting...")
+	log.Printf("Version updater starting...")
 	log.Printf("Workspace root: %s", r.config.WorkspaceRoot)
 	log.Printf("Will process %d versions", r.config.Count)
 	log.Printf("Cache directory: %s", r.config.CacheDir)
-	log.Printf("Output file: %s", r.config.OutputFile)
 
+	unlock, err := lockDir(r.resolvePath(r.config.CacheDir))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var errs []error
+	for _, tool := range r.tools() {
+		if err := r.runTool(ctx, tool); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tool.Name, err))
+		}
+	}
+
+	if err := r.writeChangelog(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	log.Println("Done!")
+	return nil
+}
+
+// runTool executes the version update workflow for a single tool.
+func (r *Runner) runTool(ctx context.Context, tool Tool) error {
+	log.Printf("Updating %s from %s...", tool.Name, tool.Repo)
+
+	// Ensure output directories exist
+	outputs := tool.AllOutputs()
+	for _, out := range outputs {
+		if err := EnsureOutputDirectory(r.resolvePath(out.Path)); err != nil {
+			return err
+		}
+	}
+
+	templateData, err := r.loadTemplateData(ctx, tool)
+	if err != nil {
+		return err
+	}
+
+	// Read the current data before it is overwritten, for the changelog
+	previous, err := LoadPreviousData(tool, r.resolvePath)
+	if err != nil {
+		log.Printf("Warning: cannot read previous version data, changelog lists all versions as added: %v", err)
+	}
+
+	// Generate output files
+	log.Println("Generating output files...")
+	for _, out := range outputs {
+		absOutputFile := r.resolvePath(out.Path)
+		if out.Template != "" {
+			out.Template = r.resolvePath(out.Template)
+		}
+		changed, err := GenerateOutput(templateData, out, absOutputFile)
+		if err != nil {
+			return fmt.Errorf("failed to generate output file %s: %w", out.Path, err)
+		}
+		if changed {
+			log.Printf("Successfully generated %s (%s)", absOutputFile, out.Format)
+		} else {
+			log.Printf("Unchanged %s (%s)", absOutputFile, out.Format)
+		}
+	}
+
+	log.Printf("Default version: %s", templateData.DefaultVersion)
+
+	r.changelogs = append(r.changelogs, NewChangelog(tool.Name, previous, templateData))
+
+	return nil
+}
+
+// loadTemplateData fetches a tool's releases, loads their checksums from the cache or the
+// release source, and returns the data its outputs are rendered from.
+func (r *Runner) loadTemplateData(ctx context.Context, tool Tool) (*TemplateData, error) {
 	// Convert relative paths to absolute paths based on workspace root
-	absCacheDir, absOutputFile := r.resolveAbsolutePaths()
+	absCacheDir := filepath.Join(r.resolvePath(r.config.CacheDir), tool.CacheSubdir)
 	log.Printf("Absolute cache directory: %s", absCacheDir)
-	log.Printf("Absolute output file: %s", absOutputFile)
 
 	// Create cache directory if it doesn't exist
 	if err := os.MkdirAll(absCacheDir, 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
-	// Ensure output directory exists
-	outputDir := filepath.Dir(absOutputFile)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	source, err := r.sourceFor(tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release source: %w", err)
+	}
+	if r.config.Offline {
+		source = offlineSource{source}
 	}
 
-	// Fetch releases from GitHub
-	log.Println("Fetching releases from GitHub...")
-	releases, err := r.client.GetLatestReleases(ctx, r.config.Count)
+	// Fetch releases from the release source, revalidating the listing of the last run
+	snapshot, err := LoadReleaseSnapshot(absCacheDir, r.config.Offline)
 	if err != nil {
-		return fmt.Errorf("failed to fetch releases: %w", err)
+		return nil, err
 	}
+	log.Println("Fetching releases...")
+	releases, err := source.GetLatestReleases(withReleaseSnapshot(ctx, snapshot), tool.Repo, max(r.config.Count, releaseWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	if err := snapshot.Save(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	releases = selectReleases(releases, r.config.Count)
 	log.Printf("Found %d releases", len(releases))
 
-	// Process each release
-	versions := r.processReleases(ctx, releases, absCacheDir)
+	// Process each release, recording the origin of new checksum files in the cache index
+	index, err := LoadCacheIndex(absCacheDir)
+	if err != nil {
+		return nil, err
+	}
+	versions := r.processReleases(ctx, tool, source, releases, absCacheDir, index)
+	if err := index.Save(); err != nil {
+		log.Printf("Warning: %v", err)
+	}
 
 	if len(versions) == 0 {
-		return fmt.Errorf("no versions were successfully processed")
+		return nil, fmt.Errorf("no versions were successfully processed")
 	}
 
 	log.Printf("Successfully processed %d versions", len(versions))
 
-	// Prepare template data
-	log.Println("Generating Starlark file...")
 	templateData := PrepareTemplateData(versions)
+	if err := templateData.ApplyDefaultVersion(tool.DefaultVersion); err != nil {
+		return nil, err
+	}
+	templateData.ToolName = tool.Name
+	templateData.VarPrefix = tool.VarPrefix
+	templateData.URLTemplate = assetURLTemplate(tool, source)
+	if r.config.OmitTimestamp {
+		templateData.GeneratedAt = ""
+	}
+	return templateData, nil
+}
 
-	// Generate output file
-	if err := GenerateStarlarkFile(templateData, absOutputFile); err != nil {
-		return fmt.Errorf("failed to generate output file: %w", err)
+// writeChangelog writes the Markdown summary of the tools processed so far.
+func (r *Runner) writeChangelog() error {
+	if len(r.changelogs) == 0 {
+		return nil
 	}
 
-	log.Printf("Successfully generated %s", absOutputFile)
-	log.Printf("Default version: %s", templateData.DefaultVersion)
-	log.Println("Done!")
+	markdown := RenderChangelogs(r.changelogs)
+	if r.config.ChangelogWriter != nil {
+		if _, err := io.WriteString(r.config.ChangelogWriter, markdown); err != nil {
+			return fmt.Errorf("failed to write changelog: %w", err)
+		}
+	}
 
-	return nil
+	changelogFile := ""
+	if r.config.ChangelogFile != "" {
+		changelogFile = r.resolvePath(r.config.ChangelogFile)
+	}
+	return WriteChangelog(markdown, changelogFile, r.config.StepSummaryFile)
 }
 
-// resolveAbsolutePaths converts relative paths to absolute based on workspace root.
-func (r *Runner) resolveAbsolutePaths() (absCacheDir, absOutputFile string) {
-	if filepath.IsAbs(r.config.CacheDir) {
-		absCacheDir = r.config.CacheDir
-	} else {
-		absCacheDir = filepath.Join(r.config.WorkspaceRoot, r.config.CacheDir)
+// tools returns the configured tools, or the default tool writing to Config.OutputFile.
+func (r *Runner) tools() []Tool {
+	if len(r.config.Tools) > 0 {
+		return r.config.Tools
 	}
 
-	if filepath.IsAbs(r.config.OutputFile) {
-		absOutputFile = r.config.OutputFile
-	} else {
-		absOutputFile = filepath.Join(r.config.WorkspaceRoot, r.config.OutputFile)
+	tool := DefaultTool()
+	if r.config.OutputFile != "" {
+		tool.OutputFile = r.config.OutputFile
 	}
+	return []Tool{tool}
+}
 
-	return absCacheDir, absOutputFile
+// sourceFor returns the release source for a tool: its own if configured, else the default client.
+func (r *Runner) sourceFor(tool Tool) (ReleaseSource, error) {
+	if tool.Source == nil {
+		return r.client, nil
+	}
+	return NewReleaseSource(*tool.Source, r.config.HTTPClient)
+}
+
+// resolvePath converts a relative path to absolute based on workspace root.
+func (r *Runner) resolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(r.config.WorkspaceRoot, path)
+}
+
+// selectReleases returns the newest count releases, plus the newest stable release of
+// each older major line so that every line keeps a default version (e.g. v1 for
+// golangci-lint configs without a schema version). The added releases are marked
+// OlderMajor. releases are newest first.
+func selectReleases(releases []Release, count int) []Release {
+	if count > len(releases) {
+		count = len(releases)
+	}
+	selected := releases[:count:count]
+
+	majors := make(map[string]bool)
+	for _, release := range selected {
+		if major, _, ok := versionLine(release.TagName); ok {
+			majors[major] = true
+		}
+	}
+	for _, release := range releases[count:] {
+		major, _, ok := versionLine(release.TagName)
+		if !ok || majors[major] || ValidateTag(release.TagName) != nil {
+			continue
+		}
+		majors[major] = true
+		release.OlderMajor = true
+		selected = append(selected, release)
+	}
+	return selected
 }
 
 // processReleases downloads and parses checksums for each release.
-func (r *Runner) processReleases(ctx context.Context, releases []Release, cacheDir string) []Version {
+func (r *Runner) processReleases(ctx context.Context, tool Tool, source ReleaseSource, releases []Release, cacheDir string, index *CacheIndex) []Version {
 	versions := make([]Version, 0, len(releases))
 
 	for _, release := range releases {
@@ -114,69 +293,158 @@ func (r *Runner) processReleases(ctx context.Context, releases []Release, cacheD
 			log.Printf("Warning: skipping release with empty tag")
 			continue
 		}
+		// Tags name cache files and are substituted into URLs and generated files
+		if err := ValidateTag(tag); err != nil {
+			log.Printf("Warning: skipping release: %v", err)
+			continue
+		}
 
 		log.Printf("Processing %s...", tag)
 
 		// Check cache
 		cacheFile := filepath.Join(cacheDir, fmt.Sprintf("%s.txt", tag))
 
-		checksumData, err := r.loadFromCacheOrDownload(ctx, cacheFile, tag)
+		checksumData, provenance, err := r.loadFromCacheOrDownload(ctx, tool, source, release, cacheFile, index)
 		if err != nil {
 			log.Printf("  Warning: %v", err)
 			continue
 		}
 
 		// Parse checksum file
-		checksums, err := ParseChecksumFile(checksumData)
+		checksums, err := ParseChecksumFileWithPattern(checksumData, tool.Pattern())
 		if err != nil {
 			log.Printf("  Warning: failed to parse checksum file: %v", err)
 			continue
 		}
 		log.Printf("  Found checksums for %d platforms", len(checksums))
 
-		versions = append(versions, Version{
-			Tag:       tag,
-			Checksums: checksums,
-		})
+		version := Version{
+			Tag:        tag,
+			Checksums:  checksums,
+			Provenance: provenance,
+			OlderMajor: release.OlderMajor,
+		}
+
+		if r.config.RecordGoVersions {
+			goVersionFile := filepath.Join(cacheDir, fmt.Sprintf("%s.goversion", tag))
+			goVersion, err := r.loadGoVersion(ctx, tool, source, release, checksums, goVersionFile)
+			if err != nil {
+				log.Printf("  Warning: failed to determine Go version: %v", err)
+			} else {
+				log.Printf("  Built with Go %s", goVersion)
+				version.GoVersion = goVersion
+			}
+		}
+
+		if r.config.RecordLicenses {
+			licenseFile := filepath.Join(cacheDir, fmt.Sprintf("%s.license", tag))
+			license, err := r.loadLicense(ctx, tool, source, release, checksums, licenseFile)
+			if err != nil {
+				log.Printf("  Warning: failed to determine license: %v", err)
+			} else {
+				log.Printf("  Licensed under %s", license)
+				version.License = license
+			}
+		}
+
+		versions = append(versions, version)
 	}
 
 	return versions
 }
 
 // loadFromCacheOrDownload attempts to load checksum data from cache, or downloads if not cached.
-func (r *Runner) loadFromCacheOrDownload(ctx context.Context, cacheFile, tag string) ([]byte, error) {
+// It returns the data with its provenance, taken from or added to the cache index.
+func (r *Runner) loadFromCacheOrDownload(ctx context.Context, tool Tool, source ReleaseSource, release Release, cacheFile string, index *CacheIndex) ([]byte, *Provenance, error) {
+	name := filepath.Base(cacheFile)
+
 	// Try cache first
 	if _, err := os.Stat(cacheFile); err == nil {
 		log.Printf("  Using cached checksum file")
 		data, err := os.ReadFile(cacheFile)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read cache file: %w", err)
+			return nil, nil, fmt.Errorf("failed to read cache file: %w", err)
 		}
-		return data, nil
-	}
-
-	// Cache miss - download
-	log.Printf("  Downloading checksum file...")
 
-	// Strip 'v' prefix from tag if present for URL
-	version := tag
-	if len(version) > 0 && version[0] == 'v' {
-		version = version[1:]
+		// A file that differs from its index entry or does not parse cleanly was truncated
+		// or corrupted and is downloaded again. Files cached before the index existed get
+		// an entry from what is known now; their download time is unknown.
+		entry, ok := index.Entries[name]
+		sum := sha256Hex(data)
+		invalid := ValidateChecksumFile(data, tool.Pattern(), release.Assets)
+		switch {
+		case ok && entry.SHA256 != sum:
+			log.Printf("  Warning: cached checksum file does not match the cache index, downloading it again")
+		case invalid != nil:
+			log.Printf("  Warning: discarding cached checksum file: %v", invalid)
+		default:
+			if !ok {
+				url := checksumURL(tool, source, release)
+				entry = CacheEntry{URL: url, SHA256: sum, Origin: OriginCache, Signature: signatureStatus(release, url)}
+				index.Entries[name] = entry
+			}
+			return data, newProvenance(sourceRepository(tool), entry), nil
+		}
 	}
 
-	url := fmt.Sprintf("https://github.com/golangci/golangci-lint/releases/download/%s/golangci-lint-%s-checksums.txt", tag, version)
-	data, err := r.client.DownloadAsset(ctx, url)
+	// Cache miss - download
+	data, url, err := r.fetchChecksumFile(ctx, tool, source, release)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download checksum file: %w", err)
+		return nil, nil, err
 	}
+	entry := CacheEntry{URL: url, SHA256: sha256Hex(data), FetchedAt: fetchTime(), Origin: OriginNetwork, Signature: signatureStatus(release, url)}
 
 	// Save to cache
-	if err := os.WriteFile(cacheFile, data, 0644); err != nil {
+	if _, err := writeFileAtomic(cacheFile, data); err != nil {
 		log.Printf("  Warning: failed to save to cache: %v", err)
 		// Continue anyway - we have the data
 	} else {
+		index.Entries[name] = entry
 		log.Printf("  Cached checksum file")
 	}
 
-	return data, nil
+	return data, newProvenance(sourceRepository(tool), entry), nil
+}
+
+// fetchChecksumFile obtains the checksum file of a release from checksumURL and returns it
+// with its URL. If a release lists assets but none is a checksum file, checksums are
+// computed by downloading each archive and the URL is empty.
+func (r *Runner) fetchChecksumFile(ctx context.Context, tool Tool, source ReleaseSource, release Release) ([]byte, string, error) {
+	url := checksumURL(tool, source, release)
+
+	if url == "" {
+		archives := archiveAssets(release.Assets, tool.Pattern())
+		if len(archives) == 0 {
+			return nil, "", fmt.Errorf("release has neither a checksum file nor archives matching %q", tool.Pattern())
+		}
+
+		log.Printf("  No checksum file found; computing checksums for %d archives...", len(archives))
+		data, err := computeChecksumFile(ctx, source, archives)
+		return data, "", err
+	}
+
+	log.Printf("  Downloading checksum file...")
+	data, err := source.DownloadAsset(ctx, url)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download checksum file: %w", err)
+	}
+
+	return data, url, nil
+}
+
+// checksumURL returns where the checksum file of a release is downloaded from. In order of
+// preference it is the URL published by the source, the release asset matching the tool's
+// checksum glob, or the conventional asset URL when the source reports no assets. It is
+// empty when the release lists assets but no checksum file.
+func checksumURL(tool Tool, source ReleaseSource, release Release) string {
+	if release.ChecksumURL != "" {
+		return release.ChecksumURL
+	}
+	if len(release.Assets) > 0 {
+		if asset, ok := findChecksumAsset(release.Assets, tool.ChecksumGlobFor(release.TagName)); ok {
+			return asset.URL
+		}
+		return ""
+	}
+	return source.AssetURL(tool.Repo, release.TagName, tool.ChecksumFileName(release.TagName))
 }
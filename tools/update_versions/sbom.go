@@ -0,0 +1,371 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// SBOM formats.
+const (
+	SBOMFormatSPDX      = "spdx"
+	SBOMFormatCycloneDX = "cyclonedx"
+)
+
+// sbomCreator names this tool in generated SBOMs.
+const sbomCreator = "update_versions"
+
+// spdxIDRegexp matches characters not allowed in an SPDX element ID.
+var spdxIDRegexp = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// SBOMPackage is one tool binary, a version on a platform, described by an SBOM.
+type SBOMPackage struct {
+	Name     string
+	Version  string
+	OS       string
+	Arch     string
+	FileName string
+	URL      string
+	SHA256   string
+	// License is an SPDX identifier, or NoAssertion if unknown.
+	License string
+}
+
+// PURL returns the package URL of the binary's download.
+func (p SBOMPackage) PURL() string {
+	return "pkg:generic/" + url.PathEscape(p.Name) + "@" + url.PathEscape(p.Version) +
+		"?download_url=" + url.QueryEscape(p.URL)
+}
+
+// ComputeSBOMPackages returns a package for every version and platform of data, newest
+// version first and then by platform. It needs data.URLTemplate to locate the archives.
+func ComputeSBOMPackages(data *TemplateData) ([]SBOMPackage, error) {
+	if data.URLTemplate == "" {
+		return nil, fmt.Errorf("an SBOM needs the archive URL of the release source")
+	}
+
+	var packages []SBOMPackage
+	for _, v := range data.Versions {
+		license := v.License
+		if license == "" {
+			license = NoAssertion
+		}
+		for _, goos := range SortedOSKeys(v.ChecksumsByOS) {
+			for _, arch := range SortedArchKeys(v.ChecksumsByOS[goos]) {
+				archiveURL := RenderURL(data.URLTemplate, v.Tag, goos, arch)
+				packages = append(packages, SBOMPackage{
+					Name:     data.ToolName,
+					Version:  v.Tag,
+					OS:       goos,
+					Arch:     arch,
+					FileName: path.Base(archiveURL),
+					URL:      archiveURL,
+					SHA256:   v.ChecksumsByOS[goos][arch],
+					License:  license,
+				})
+			}
+		}
+	}
+	return packages, nil
+}
+
+// RenderSBOM encodes packages as an SPDX 2.3 or CycloneDX 1.5 JSON document created at
+// createdAt (RFC 3339).
+func RenderSBOM(format string, packages []SBOMPackage, createdAt string) ([]byte, error) {
+	if err := checkSBOMFormat(format); err != nil {
+		return nil, err
+	}
+
+	var doc any = newSPDXDocument(packages, createdAt)
+	if format == SBOMFormatCycloneDX {
+		doc = newCycloneDXDocument(packages, createdAt)
+	}
+
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// checkSBOMFormat returns an error unless format is a supported SBOM format.
+func checkSBOMFormat(format string) error {
+	if format != SBOMFormatSPDX && format != SBOMFormatCycloneDX {
+		return fmt.Errorf("unknown SBOM format %q (want %s or %s)", format, SBOMFormatSPDX, SBOMFormatCycloneDX)
+	}
+	return nil
+}
+
+// SBOM describes the versions pinned in every configured tool's generated data. Licenses
+// are read from the cache; with Config.RecordLicenses, uncached ones are read from each
+// version's verified linux/amd64 archive.
+func (r *Runner) SBOM(ctx context.Context, format string) ([]byte, error) {
+	if err := checkSBOMFormat(format); err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockDir(r.resolvePath(r.config.CacheDir))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var packages []SBOMPackage
+	for _, tool := range r.tools() {
+		log.Printf("Describing %s...", tool.Name)
+		data, err := r.pinnedData(ctx, tool)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tool.Name, err)
+		}
+		toolPackages, err := ComputeSBOMPackages(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tool.Name, err)
+		}
+		packages = append(packages, toolPackages...)
+	}
+	return RenderSBOM(format, packages, generatedAt())
+}
+
+// pinnedData returns the versions pinned in a tool's generated data with their licenses
+// and the archive URL template of its release source.
+func (r *Runner) pinnedData(ctx context.Context, tool Tool) (*TemplateData, error) {
+	data, err := LoadPreviousData(tool, r.resolvePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version data: %w", err)
+	}
+	if data == nil || len(data.Versions) == 0 {
+		return nil, fmt.Errorf("no generated version data; run the updater first")
+	}
+
+	source, err := r.sourceFor(tool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release source: %w", err)
+	}
+	cacheDir := filepath.Join(r.resolvePath(r.config.CacheDir), tool.CacheSubdir)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	for i := range data.Versions {
+		v := &data.Versions[i]
+		if err := ValidateTag(v.Tag); err != nil {
+			return nil, err
+		}
+		licenseFile := filepath.Join(cacheDir, v.Tag+".license")
+		if !r.config.RecordLicenses {
+			if content, err := os.ReadFile(licenseFile); err == nil {
+				v.License = strings.TrimSpace(string(content))
+			}
+			continue
+		}
+		license, err := r.loadLicense(ctx, tool, source, Release{TagName: v.Tag}, platformChecksums(v.ChecksumsByOS), licenseFile)
+		if err != nil {
+			log.Printf("  Warning: %s: failed to determine license: %v", v.Tag, err)
+			continue
+		}
+		v.License = license
+	}
+
+	data.ToolName = tool.Name
+	data.VarPrefix = tool.VarPrefix
+	data.URLTemplate = assetURLTemplate(tool, source)
+	return data, nil
+}
+
+// platformChecksums converts os -> arch -> sha256 into a checksum per platform.
+func platformChecksums(byOS map[string]map[string]string) map[Platform]string {
+	checksums := make(map[Platform]string)
+	for goos, archs := range byOS {
+		for arch, sha256 := range archs {
+			checksums[Platform{OS: goos, Arch: arch}] = sha256
+		}
+	}
+	return checksums
+}
+
+// spdxDocument is the subset of an SPDX 2.3 JSON document the updater emits.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID                string            `json:"SPDXID"`
+	Name                  string            `json:"name"`
+	VersionInfo           string            `json:"versionInfo"`
+	PackageFileName       string            `json:"packageFileName"`
+	DownloadLocation      string            `json:"downloadLocation"`
+	FilesAnalyzed         bool              `json:"filesAnalyzed"`
+	Checksums             []spdxChecksum    `json:"checksums"`
+	LicenseConcluded      string            `json:"licenseConcluded"`
+	LicenseDeclared       string            `json:"licenseDeclared"`
+	CopyrightText         string            `json:"copyrightText"`
+	PrimaryPackagePurpose string            `json:"primaryPackagePurpose"`
+	ExternalRefs          []spdxExternalRef `json:"externalRefs"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// newSPDXDocument describes packages in an SPDX document. The namespace is derived from
+// the package digests, so the same selection always yields the same document.
+func newSPDXDocument(packages []SBOMPackage, createdAt string) *spdxDocument {
+	doc := &spdxDocument{
+		SPDXVersion:   "SPDX-2.3",
+		DataLicense:   "CC0-1.0",
+		SPDXID:        "SPDXRef-DOCUMENT",
+		Name:          "tool-binaries",
+		CreationInfo:  spdxCreationInfo{Created: createdAt, Creators: []string{"Tool: " + sbomCreator}},
+		Packages:      []spdxPackage{},
+		Relationships: []spdxRelationship{},
+	}
+
+	digest := sha256.New()
+	for _, p := range packages {
+		id := "SPDXRef-Package-" + spdxIDRegexp.ReplaceAllString(p.Name+"-"+p.Version+"-"+p.OS+"-"+p.Arch, "-")
+		doc.Packages = append(doc.Packages, spdxPackage{
+			SPDXID:                id,
+			Name:                  p.Name,
+			VersionInfo:           p.Version,
+			PackageFileName:       p.FileName,
+			DownloadLocation:      p.URL,
+			Checksums:             []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: p.SHA256}},
+			LicenseConcluded:      NoAssertion,
+			LicenseDeclared:       p.License,
+			CopyrightText:         NoAssertion,
+			PrimaryPackagePurpose: "APPLICATION",
+			ExternalRefs:          []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: p.PURL()}},
+		})
+		doc.Relationships = append(doc.Relationships, spdxRelationship{
+			SPDXElementID:      doc.SPDXID,
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+		digest.Write([]byte(p.URL + " " + p.SHA256 + "\n"))
+	}
+	doc.DocumentNamespace = "https://spdx.org/spdxdocs/" + doc.Name + "-" + hex.EncodeToString(digest.Sum(nil))
+	return doc
+}
+
+// cycloneDXDocument is the subset of a CycloneDX 1.5 JSON BOM the updater emits.
+type cycloneDXDocument struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string         `json:"timestamp"`
+	Tools     cycloneDXTools `json:"tools"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type               string              `json:"type"`
+	BOMRef             string              `json:"bom-ref,omitempty"`
+	Name               string              `json:"name"`
+	Version            string              `json:"version,omitempty"`
+	Hashes             []cycloneDXHash     `json:"hashes,omitempty"`
+	Licenses           []cycloneDXLicense  `json:"licenses,omitempty"`
+	PURL               string              `json:"purl,omitempty"`
+	ExternalReferences []cycloneDXExtRef   `json:"externalReferences,omitempty"`
+	Properties         []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXLicense struct {
+	License struct {
+		ID string `json:"id"`
+	} `json:"license"`
+}
+
+type cycloneDXExtRef struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// newCycloneDXDocument describes packages as CycloneDX components. Unknown licenses are
+// left out, as CycloneDX has no NOASSERTION value.
+func newCycloneDXDocument(packages []SBOMPackage, createdAt string) *cycloneDXDocument {
+	doc := &cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Timestamp: createdAt,
+			Tools:     cycloneDXTools{Components: []cycloneDXComponent{{Type: "application", Name: sbomCreator}}},
+		},
+		Components: []cycloneDXComponent{},
+	}
+
+	for _, p := range packages {
+		component := cycloneDXComponent{
+			Type:               "application",
+			BOMRef:             p.PURL(),
+			Name:               p.Name,
+			Version:            p.Version,
+			Hashes:             []cycloneDXHash{{Alg: "SHA-256", Content: p.SHA256}},
+			PURL:               p.PURL(),
+			ExternalReferences: []cycloneDXExtRef{{Type: "distribution", URL: p.URL}},
+			Properties: []cycloneDXProperty{
+				{Name: sbomCreator + ":os", Value: p.OS},
+				{Name: sbomCreator + ":arch", Value: p.Arch},
+			},
+		}
+		if p.License != NoAssertion {
+			var license cycloneDXLicense
+			license.License.ID = p.License
+			component.Licenses = []cycloneDXLicense{license}
+		}
+		doc.Components = append(doc.Components, component)
+	}
+	return doc
+}
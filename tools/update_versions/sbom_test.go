@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sbomTestPackages returns the packages of toolchainsTestData with a license on the newest version.
+func sbomTestPackages(t *testing.T) []SBOMPackage {
+	t.Helper()
+
+	data := toolchainsTestData()
+	data.Versions[0].License = "GPL-3.0-only"
+	packages, err := ComputeSBOMPackages(data)
+	require.NoError(t, err, "ComputeSBOMPackages() should succeed")
+	return packages
+}
+
+func TestComputeSBOMPackages(t *testing.T) {
+	packages := sbomTestPackages(t)
+
+	require.Len(t, packages, 3, "ComputeSBOMPackages() should list every version and platform")
+	assert.Equal(t, SBOMPackage{
+		Name:     "golangci-lint",
+		Version:  "v2.6.1",
+		OS:       "linux",
+		Arch:     "armv7",
+		FileName: "golangci-lint-2.6.1-linux-armv7.tar.gz",
+		URL:      "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-armv7.tar.gz",
+		SHA256:   "aaa",
+		License:  "GPL-3.0-only",
+	}, packages[0], "ComputeSBOMPackages() should describe the archive")
+	assert.Equal(t, NoAssertion, packages[2].License, "ComputeSBOMPackages() should make no assertion about unrecorded licenses")
+
+	_, err := ComputeSBOMPackages(&TemplateData{ToolName: "golangci-lint"})
+	assert.Error(t, err, "ComputeSBOMPackages() should require a URL template")
+}
+
+func TestRenderSBOM_SPDX(t *testing.T) {
+	content, err := RenderSBOM(SBOMFormatSPDX, sbomTestPackages(t), "2025-11-01T00:00:00Z")
+	require.NoError(t, err, "RenderSBOM() should succeed")
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(content, &doc), "SBOM should be valid JSON")
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion, "SBOM should be SPDX 2.3")
+	assert.Equal(t, "2025-11-01T00:00:00Z", doc.CreationInfo.Created, "SBOM should record its creation time")
+	require.Len(t, doc.Packages, 3, "SBOM should list every package")
+	require.Len(t, doc.Relationships, 3, "SBOM should describe every package")
+
+	pkg := doc.Packages[1]
+	assert.Equal(t, "SPDXRef-Package-golangci-lint-v2.6.1-windows-amd64", pkg.SPDXID, "package IDs should be valid SPDX IDs")
+	assert.Equal(t, "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-windows-amd64.zip", pkg.DownloadLocation, "package should record its download URL")
+	assert.Equal(t, []spdxChecksum{{Algorithm: "SHA256", ChecksumValue: "bbb"}}, pkg.Checksums, "package should record its digest")
+	assert.Equal(t, "GPL-3.0-only", pkg.LicenseDeclared, "package should declare its license")
+	assert.Equal(t, pkg.SPDXID, doc.Relationships[1].RelatedSPDXElement, "document should describe the package")
+
+	again, err := RenderSBOM(SBOMFormatSPDX, sbomTestPackages(t), "2025-11-01T00:00:00Z")
+	require.NoError(t, err, "RenderSBOM() should succeed")
+	assert.Equal(t, string(content), string(again), "RenderSBOM() should be deterministic")
+}
+
+func TestRenderSBOM_CycloneDX(t *testing.T) {
+	content, err := RenderSBOM(SBOMFormatCycloneDX, sbomTestPackages(t), "2025-11-01T00:00:00Z")
+	require.NoError(t, err, "RenderSBOM() should succeed")
+
+	var doc cycloneDXDocument
+	require.NoError(t, json.Unmarshal(content, &doc), "SBOM should be valid JSON")
+	assert.Equal(t, "CycloneDX", doc.BOMFormat, "SBOM should be CycloneDX")
+	require.Len(t, doc.Components, 3, "SBOM should list every package")
+
+	component := doc.Components[0]
+	assert.Equal(t, "v2.6.1", component.Version, "component should record its version")
+	assert.Equal(t, []cycloneDXHash{{Alg: "SHA-256", Content: "aaa"}}, component.Hashes, "component should record its digest")
+	require.Len(t, component.Licenses, 1, "component should declare its license")
+	assert.Equal(t, "GPL-3.0-only", component.Licenses[0].License.ID, "component should declare its license")
+	assert.Equal(t, "pkg:generic/golangci-lint@v2.6.1?download_url=https%3A%2F%2Fgithub.com%2Fgolangci%2Fgolangci-lint%2Freleases%2Fdownload%2Fv2.6.1%2Fgolangci-lint-2.6.1-linux-armv7.tar.gz",
+		component.PURL, "component should have a package URL")
+	assert.Empty(t, doc.Components[2].Licenses, "component should omit an unknown license")
+
+	_, err = RenderSBOM("swid", nil, "")
+	assert.Error(t, err, "RenderSBOM() should reject unknown formats")
+}
+
+func TestRunner_SBOM_RecordsLicense(t *testing.T) {
+	tempDir := t.TempDir()
+	cacheDir := filepath.Join(tempDir, "cache")
+	archive := makeTarGz(t, map[string][]byte{
+		"golangci-lint-2.6.1-linux-amd64/golangci-lint": []byte("binary"),
+		"golangci-lint-2.6.1-linux-amd64/LICENSE":       []byte("GNU GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n"),
+	})
+
+	mock := NewMockGitHubClient()
+	mock.AddRelease("v2.6.1")
+	mock.AddAsset(
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		[]byte(sha256Hex(archive)+"  golangci-lint-2.6.1-linux-amd64.tar.gz\n"),
+	)
+	mock.AddAsset("https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-amd64.tar.gz", archive)
+
+	config := Config{Count: 1, CacheDir: cacheDir, OutputFile: "versions.bzl", WorkspaceRoot: tempDir}
+	_, err := NewRunner(config, mock).SBOM(context.Background(), SBOMFormatSPDX)
+	assert.Error(t, err, "Runner.SBOM() should require generated version data")
+	require.NoError(t, NewRunner(config, mock).Run(context.Background()), "Runner.Run() should succeed")
+
+	// A newer release that is not pinned must not be described
+	mock.AddRelease("v2.7.0")
+
+	content, err := NewRunner(config, mock).SBOM(context.Background(), SBOMFormatSPDX)
+	require.NoError(t, err, "Runner.SBOM() should succeed")
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(content, &doc), "SBOM should be valid JSON")
+	require.Len(t, doc.Packages, 1, "SBOM should describe the pinned archive")
+	assert.Equal(t, "v2.6.1", doc.Packages[0].VersionInfo, "SBOM should describe the pinned version")
+	assert.Equal(t, NoAssertion, doc.Packages[0].LicenseDeclared, "SBOM should not download archives by default")
+	assert.NoFileExists(t, filepath.Join(cacheDir, "v2.6.1.license"), "SBOM should not download archives by default")
+
+	config.RecordLicenses = true
+	content, err = NewRunner(config, mock).SBOM(context.Background(), SBOMFormatSPDX)
+	require.NoError(t, err, "Runner.SBOM() should succeed")
+	require.NoError(t, json.Unmarshal(content, &doc), "SBOM should be valid JSON")
+	require.Len(t, doc.Packages, 1, "SBOM should describe the pinned archive")
+	assert.Equal(t, "GPL-3.0-only", doc.Packages[0].LicenseDeclared, "SBOM should declare the license read from the archive")
+	assert.Equal(t, sha256Hex(archive), doc.Packages[0].Checksums[0].ChecksumValue, "SBOM should use the pinned digest")
+
+	cached, err := os.ReadFile(filepath.Join(cacheDir, "v2.6.1.license"))
+	require.NoError(t, err, "Runner.SBOM() should cache the license")
+	assert.Equal(t, "GPL-3.0-only\n", string(cached), "cached license should match")
+
+	// The cached license is used without downloading
+	config.RecordLicenses = false
+	content, err = NewRunner(config, mock).SBOM(context.Background(), SBOMFormatSPDX)
+	require.NoError(t, err, "Runner.SBOM() should succeed")
+	require.NoError(t, json.Unmarshal(content, &doc), "SBOM should be valid JSON")
+	assert.Equal(t, "GPL-3.0-only", doc.Packages[0].LicenseDeclared, "SBOM should use the cached license")
+
+	_, err = NewRunner(config, mock).SBOM(context.Background(), "swid")
+	assert.Error(t, err, "Runner.SBOM() should reject unknown formats")
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Source types accepted in SourceConfig.Type.
+const (
+	SourceGitHub    = "github"
+	SourceGitLab    = "gitlab"
+	SourceGitea     = "gitea"
+	SourceHTTPIndex = "http-index"
+)
+
+// Release represents a published release with basic information.
+type Release struct {
+	TagName string
+	// ChecksumURL is set by sources that publish the checksum file location directly.
+	ChecksumURL string
+	// Assets lists the files attached to the release. Empty if the source does not report them.
+	Assets []Asset
+	// OlderMajor marks a release kept beyond the --count window as the newest of an older
+	// major line. Set by selectReleases, not by sources.
+	OlderMajor bool
+}
+
+// Asset describes a file attached to a release.
+type Asset struct {
+	Name string
+	// Size is the asset size in bytes, or 0 if unknown.
+	Size        int64
+	URL         string
+	ContentType string
+}
+
+// ReleaseSource lists releases of a repository and downloads their assets.
+type ReleaseSource interface {
+	// GetLatestReleases returns up to count releases, newest first.
+	GetLatestReleases(ctx context.Context, repo string, count int) ([]Release, error)
+	// AssetURL returns the download URL of a named asset attached to a release.
+	AssetURL(repo, tag, name string) string
+	// DownloadAsset downloads an asset from a URL and returns the contents.
+	DownloadAsset(ctx context.Context, url string) ([]byte, error)
+}
+
+// SourceConfig selects and configures the release source for a tool.
+type SourceConfig struct {
+	// Type is one of github (default), gitlab, gitea or http-index.
+	Type string `json:"type,omitempty"`
+	// URL is the forge base URL (e.g. https://gitlab.example.com), or the index
+	// document URL for http-index. Empty means github.com for the github type.
+	URL string `json:"url,omitempty"`
+	// TokenEnv names an environment variable holding an API token.
+	TokenEnv string `json:"token_env,omitempty"`
+}
+
+// Validate checks the source type and required fields.
+func (c *SourceConfig) Validate() error {
+	switch c.Type {
+	case "", SourceGitHub:
+		return nil
+	case SourceGitLab, SourceGitea, SourceHTTPIndex:
+		if c.URL == "" {
+			return fmt.Errorf("source type %q requires url", c.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown source type %q", c.Type)
+	}
+}
+
+// NewReleaseSource creates the release source described by cfg, making requests through
+// httpClient; nil means a client with the default HTTPConfig.
+func NewReleaseSource(cfg SourceConfig, httpClient *http.Client) (ReleaseSource, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	token := ""
+	if cfg.TokenEnv != "" {
+		token = os.Getenv(cfg.TokenEnv)
+	}
+
+	switch cfg.Type {
+	case SourceGitLab:
+		return NewGitLabClient(httpClient, cfg.URL, token), nil
+	case SourceGitea:
+		return NewGiteaClient(httpClient, cfg.URL, token), nil
+	case SourceHTTPIndex:
+		return NewHTTPIndexSource(httpClient, cfg.URL), nil
+	default:
+		if cfg.URL == "" {
+			return NewGitHubClient(httpClient).WithToken(token), nil
+		}
+		client, err := NewGitHubEnterpriseClient(httpClient, cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		return client.WithToken(token), nil
+	}
+}
+
+// httpGet performs a GET request with the given headers and returns the body of a 200
+// response, failing if it is larger than limit bytes.
+func httpGet(ctx context.Context, client *http.Client, url string, headers map[string]string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download asset: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("response of %d bytes exceeds %d", resp.ContentLength, limit)
+	}
+
+	body, err := readLimited(resp.Body, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return body, nil
+}
+
+// sameOrigin reports whether rawURL has the scheme and host of baseURL.
+func sameOrigin(rawURL, baseURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host)
+}
+
+// trimBaseURL removes trailing slashes so paths can be appended with "/".
+func trimBaseURL(baseURL string) string {
+	return strings.TrimRight(baseURL, "/")
+}
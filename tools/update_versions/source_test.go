@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReleaseServer starts a test server that serves fixed responses by request path.
+// It records the last request's headers in lastHeaders.
+func newReleaseServer(t *testing.T, routes map[string]string, lastHeaders *http.Header) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if lastHeaders != nil {
+			*lastHeaders = r.Header.Clone()
+		}
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGitHubEnterpriseClient(t *testing.T) {
+	server := newReleaseServer(t, map[string]string{
+		"/api/v3/repos/tools/golangci-lint/releases":                  `[{"tag_name": "v2.6.1"}, {"tag_name": "v2.6.0"}]`,
+		"/tools/golangci-lint/releases/download/v2.6.1/checksums.txt": "checksum data",
+	}, nil)
+
+	client, err := NewGitHubEnterpriseClient(nil, server.URL)
+	require.NoError(t, err, "NewGitHubEnterpriseClient() should succeed")
+
+	ctx := context.Background()
+	releases, err := client.GetLatestReleases(ctx, "tools/golangci-lint", 10)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	assert.Equal(t, []Release{{TagName: "v2.6.1"}, {TagName: "v2.6.0"}}, releases, "GetLatestReleases() should return releases in order")
+
+	url := client.AssetURL("tools/golangci-lint", "v2.6.1", "checksums.txt")
+	assert.Equal(t, server.URL+"/tools/golangci-lint/releases/download/v2.6.1/checksums.txt", url, "AssetURL() should use the enterprise host")
+
+	data, err := client.DownloadAsset(ctx, url)
+	require.NoError(t, err, "DownloadAsset() should succeed")
+	assert.Equal(t, "checksum data", string(data), "DownloadAsset() should return asset contents")
+}
+
+func TestGitHubClient_AssetURL(t *testing.T) {
+	client := NewGitHubClient(nil)
+
+	assert.Equal(t,
+		"https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt",
+		client.AssetURL("golangci/golangci-lint", "v2.6.1", "golangci-lint-2.6.1-checksums.txt"),
+		"AssetURL() should use github.com release download layout",
+	)
+}
+
+func TestGitLabClient(t *testing.T) {
+	var headers http.Header
+	server := newReleaseServer(t, map[string]string{
+		"/api/v4/projects/group/sub/tool/releases": `[
+			{"tag_name": "v1.3.0", "upcoming_release": true},
+			{"tag_name": "v1.2.0"},
+			{"tag_name": "v1.1.0"}
+		]`,
+	}, &headers)
+
+	client := NewGitLabClient(nil, server.URL+"/", "secret")
+
+	releases, err := client.GetLatestReleases(context.Background(), "group/sub/tool", 10)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	assert.Equal(t, []Release{{TagName: "v1.2.0"}, {TagName: "v1.1.0"}}, releases, "GetLatestReleases() should skip upcoming releases")
+	assert.Equal(t, "secret", headers.Get("PRIVATE-TOKEN"), "GetLatestReleases() should send the token")
+
+	assert.Equal(t,
+		server.URL+"/group/sub/tool/-/releases/v1.2.0/downloads/checksums.txt",
+		client.AssetURL("group/sub/tool", "v1.2.0", "checksums.txt"),
+		"AssetURL() should use the release permalink layout",
+	)
+}
+
+func TestGiteaClient(t *testing.T) {
+	var headers http.Header
+	server := newReleaseServer(t, map[string]string{
+		"/api/v1/repos/owner/tool/releases": `[
+			{"tag_name": "v0.3.0", "draft": true},
+			{"tag_name": "v0.2.0"}
+		]`,
+		"/owner/tool/releases/download/v0.2.0/checksums.txt": "gitea checksums",
+	}, &headers)
+
+	client := NewGiteaClient(nil, server.URL, "secret")
+	ctx := context.Background()
+
+	releases, err := client.GetLatestReleases(ctx, "owner/tool", 10)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	assert.Equal(t, []Release{{TagName: "v0.2.0"}}, releases, "GetLatestReleases() should skip drafts")
+	assert.Equal(t, "token secret", headers.Get("Authorization"), "GetLatestReleases() should send the token")
+
+	data, err := client.DownloadAsset(ctx, client.AssetURL("owner/tool", "v0.2.0", "checksums.txt"))
+	require.NoError(t, err, "DownloadAsset() should succeed")
+	assert.Equal(t, "gitea checksums", string(data), "DownloadAsset() should return asset contents")
+
+	_, err = client.GetLatestReleases(ctx, "tool", 10)
+	assert.Error(t, err, "GetLatestReleases() should reject repo without owner")
+}
+
+func TestForgeClients_AssetTokenScope(t *testing.T) {
+	var assetHeaders, forgeHeaders http.Header
+	assets := newReleaseServer(t, map[string]string{"/files/checksums.txt": "external"}, &assetHeaders)
+	forge := newReleaseServer(t, map[string]string{"/files/checksums.txt": "internal"}, &forgeHeaders)
+
+	tests := []struct {
+		name   string
+		source ReleaseSource
+		header string
+	}{
+		{"gitlab", NewGitLabClient(nil, forge.URL, "secret"), "PRIVATE-TOKEN"},
+		{"gitea", NewGiteaClient(nil, forge.URL, "secret"), "Authorization"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.source.DownloadAsset(context.Background(), assets.URL+"/files/checksums.txt")
+			require.NoError(t, err, "DownloadAsset() should succeed")
+			assert.Equal(t, "external", string(data), "DownloadAsset() should return asset contents")
+			assert.Empty(t, assetHeaders.Get(tt.header), "the token should not be sent to another host")
+
+			_, err = tt.source.DownloadAsset(context.Background(), forge.URL+"/files/checksums.txt")
+			require.NoError(t, err, "DownloadAsset() should succeed")
+			assert.NotEmpty(t, forgeHeaders.Get(tt.header), "the token should be sent to the instance")
+		})
+	}
+}
+
+func TestSameOrigin(t *testing.T) {
+	assert.True(t, sameOrigin("https://gitlab.example.com/a/b", "https://GitLab.example.com"), "hosts should match case-insensitively")
+	assert.False(t, sameOrigin("http://gitlab.example.com/a", "https://gitlab.example.com"), "a different scheme should not match")
+	assert.False(t, sameOrigin("https://gitlab.example.com:8443/a", "https://gitlab.example.com"), "a different port should not match")
+	assert.False(t, sameOrigin("https://cdn.example.com/a", "https://gitlab.example.com"), "a different host should not match")
+}
+
+func TestHTTPIndexSource(t *testing.T) {
+	server := newReleaseServer(t, map[string]string{
+		"/mirror/index.json": `{"releases": [
+			{"tag": "v2.6.1", "checksum_url": "v2.6.1/sums.txt"},
+			{"tag": "v2.6.0", "checksum_url": "https://elsewhere.example.com/v2.6.0.txt"},
+			{"tag": "v2.5.0"}
+		]}`,
+	}, nil)
+
+	source := NewHTTPIndexSource(nil, server.URL+"/mirror/index.json")
+
+	releases, err := source.GetLatestReleases(context.Background(), "", 2)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	assert.Equal(t, []Release{
+		{TagName: "v2.6.1", ChecksumURL: server.URL + "/mirror/v2.6.1/sums.txt"},
+		{TagName: "v2.6.0", ChecksumURL: "https://elsewhere.example.com/v2.6.0.txt"},
+	}, releases, "GetLatestReleases() should resolve checksum URLs and honour count")
+
+	assert.Equal(t, server.URL+"/mirror/v2.5.0/checksums.txt", source.AssetURL("", "v2.5.0", "checksums.txt"),
+		"AssetURL() should resolve relative to the index")
+}
+
+func TestHTTPIndexSource_InvalidIndex(t *testing.T) {
+	server := newReleaseServer(t, map[string]string{"/index.json": "not json"}, nil)
+
+	_, err := NewHTTPIndexSource(nil, server.URL+"/index.json").GetLatestReleases(context.Background(), "", 10)
+	assert.Error(t, err, "GetLatestReleases() should reject malformed index")
+}
+
+func TestNewReleaseSource(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    SourceConfig
+		wantType  ReleaseSource
+		wantError bool
+	}{
+		{"default github", SourceConfig{}, &GitHubClient{}, false},
+		{"github enterprise", SourceConfig{Type: SourceGitHub, URL: "https://github.example.com"}, &GitHubClient{}, false},
+		{"gitlab", SourceConfig{Type: SourceGitLab, URL: "https://gitlab.com"}, &GitLabClient{}, false},
+		{"gitea", SourceConfig{Type: SourceGitea, URL: "https://gitea.com"}, &GiteaClient{}, false},
+		{"http index", SourceConfig{Type: SourceHTTPIndex, URL: "https://mirror.example.com/index.json"}, &HTTPIndexSource{}, false},
+		{"unknown type", SourceConfig{Type: "svn"}, nil, true},
+		{"gitea without url", SourceConfig{Type: SourceGitea}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewReleaseSource(tt.config, nil)
+			if tt.wantError {
+				assert.Error(t, err, "NewReleaseSource() should return error")
+				return
+			}
+
+			require.NoError(t, err, "NewReleaseSource() should succeed")
+			assert.IsType(t, tt.wantType, source, "NewReleaseSource() should return the configured source type")
+		})
+	}
+}
+
+func TestRunner_Run_PerToolSource(t *testing.T) {
+	server := newReleaseServer(t, map[string]string{
+		"/api/v1/repos/mirror/golangci-lint/releases":                                      `[{"tag_name": "v2.6.1"}]`,
+		"/mirror/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-checksums.txt": "fff6666666666666666666666666666666666666666666666666666666666666  golangci-lint-2.6.1-linux-amd64.tar.gz\n",
+	}, nil)
+
+	tempDir := t.TempDir()
+	tool := Tool{
+		Name:       "golangci-lint",
+		Repo:       "mirror/golangci-lint",
+		OutputFile: "versions.bzl",
+		VarPrefix:  "GOLANGCI",
+		Source:     &SourceConfig{Type: SourceGitea, URL: server.URL},
+	}
+	require.NoError(t, tool.Validate(), "Tool.Validate() should succeed")
+
+	config := Config{
+		Count:         1,
+		CacheDir:      filepath.Join(tempDir, "cache"),
+		WorkspaceRoot: tempDir,
+		Tools:         []Tool{tool},
+	}
+
+	// The default client has no releases; the tool's own source must be used
+	err := NewRunner(config, NewMockGitHubClient()).Run(context.Background())
+	require.NoError(t, err, "Runner.Run() should succeed")
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "versions.bzl"))
+	require.NoError(t, err, "Failed to read output file")
+	assert.Contains(t, string(content), "fff6666666666666666666666666666666666666666666666666666666666666", "Runner.Run() should use checksums from the tool's source")
+}
+
+func TestGiteaClient_Assets(t *testing.T) {
+	server := newReleaseServer(t, map[string]string{
+		"/api/v1/repos/owner/tool/releases": `[{"tag_name": "v0.2.0", "assets": [
+			{"name": "tool-0.2.0-linux-amd64.tar.gz", "size": 42, "browser_download_url": "https://gitea.example.com/a.tar.gz"}
+		]}]`,
+	}, nil)
+
+	releases, err := NewGiteaClient(nil, server.URL, "").GetLatestReleases(context.Background(), "owner/tool", 10)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	require.Len(t, releases, 1, "GetLatestReleases() should return one release")
+	assert.Equal(t, []Asset{{Name: "tool-0.2.0-linux-amd64.tar.gz", Size: 42, URL: "https://gitea.example.com/a.tar.gz"}},
+		releases[0].Assets, "GetLatestReleases() should report release assets")
+}
+
+func TestGitHubEnterpriseClient_Assets(t *testing.T) {
+	server := newReleaseServer(t, map[string]string{
+		"/api/v3/repos/tools/lint/releases": `[{"tag_name": "v1.0.0", "assets": [
+			{"name": "checksums.txt", "size": 7, "content_type": "text/plain", "browser_download_url": "https://ghe.example.com/sums"}
+		]}]`,
+	}, nil)
+
+	client, err := NewGitHubEnterpriseClient(nil, server.URL)
+	require.NoError(t, err, "NewGitHubEnterpriseClient() should succeed")
+
+	releases, err := client.GetLatestReleases(context.Background(), "tools/lint", 10)
+	require.NoError(t, err, "GetLatestReleases() should succeed")
+	require.Len(t, releases, 1, "GetLatestReleases() should return one release")
+	assert.Equal(t, []Asset{{Name: "checksums.txt", Size: 7, URL: "https://ghe.example.com/sums", ContentType: "text/plain"}},
+		releases[0].Assets, "GetLatestReleases() should report release assets")
+}
@@ -1,29 +1,48 @@
 package main
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"text/template"
 	"time"
 )
 
-//go:embed template.bzl.tmpl
+//go:embed template.bzl.tmpl loader.bzl.tmpl toolchains.bzl.tmpl
 var templateFS embed.FS
 
 // TemplateData holds the data for generating the Starlark file.
 type TemplateData struct {
+	// ToolName and VarPrefix identify the tool; they default to golangci-lint and GOLANGCI.
+	ToolName       string
+	VarPrefix      string
 	GeneratedAt    string
 	DefaultVersion string
 	Versions       []VersionData
+	// DefaultVersions holds the default of each major line; the line of DefaultVersion uses it.
+	DefaultVersions []MajorDefault
+	// Aliases maps latest, major and minor lines to their newest stable version.
+	Aliases []Alias
+	// Platforms lists every platform of Versions with its Bazel constraints.
+	Platforms []PlatformData
+	// URLTemplate is the archive download URL with {tag}, {version}, {os}, {arch}
+	// and {ext} placeholders, for use with renderURL.
+	URLTemplate string
 }
 
 // VersionData represents version data organized for template rendering.
 type VersionData struct {
 	Tag           string
 	ChecksumsByOS map[string]map[string]string // os -> arch -> sha256
+	GoVersion     string                       // empty if not recorded
+	License       string                       // SPDX identifier; empty if not recorded
+	Provenance    *Provenance                  // nil if not known
+	OlderMajor    bool                         // kept beyond --count for its major line; not a policy default
 }
 
 // EnsureOutputDirectory ensures the output directory exists.
@@ -35,53 +54,106 @@ func EnsureOutputDirectory(outputPath string) error {
 	return nil
 }
 
-// GenerateStarlarkFile generates the versions.bzl file from template.
-func GenerateStarlarkFile(data *TemplateData, outputPath string) error {
-	// Create template with custom functions
-	funcMap := template.FuncMap{
-		"SortedOSKeys":   SortedOSKeys,
-		"SortedArchKeys": SortedArchKeys,
+// GenerateStarlarkFile generates the versions.bzl file from template and reports whether
+// outputPath changed. The rendered file is executed and checked before it replaces
+// outputPath, so a broken render leaves the old file.
+func GenerateStarlarkFile(data *TemplateData, outputPath string) (bool, error) {
+	content, err := RenderStarlark(data)
+	if err != nil {
+		return false, err
+	}
+	if err := ValidateStarlark(filepath.Base(outputPath), content, withToolDefaults(data)); err != nil {
+		return false, err
 	}
+	return writeStarlarkFileAtomic(outputPath, content)
+}
 
+// RenderStarlark renders the embedded Starlark template with the given data.
+func RenderStarlark(data *TemplateData) ([]byte, error) {
+	return renderTemplate("template.bzl.tmpl", withToolDefaults(data))
+}
+
+// RenderCustomTemplate renders a user-supplied template file with the given data.
+func RenderCustomTemplate(templatePath string, data *TemplateData) ([]byte, error) {
+	tmpl, err := template.New(filepath.Base(templatePath)).Funcs(templateFuncs()).ParseFiles(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return executeTemplate(tmpl, withToolDefaults(data))
+}
+
+// renderTemplate executes one of the embedded templates.
+func renderTemplate(name string, data any) ([]byte, error) {
 	// Parse template
-	tmpl, err := template.New("template.bzl.tmpl").Funcs(funcMap).ParseFS(templateFS, "template.bzl.tmpl")
+	tmpl, err := template.New(name).Funcs(templateFuncs()).ParseFS(templateFS, name)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return executeTemplate(tmpl, data)
+}
+
+// executeTemplate executes a parsed template into memory.
+func executeTemplate(tmpl *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// withToolDefaults fills in tool identity for callers that predate multi-tool support.
+func withToolDefaults(data *TemplateData) *TemplateData {
+	defaults := DefaultTool()
+	filled := *data
+	if filled.ToolName == "" {
+		filled.ToolName = defaults.Name
+	}
+	if filled.VarPrefix == "" {
+		filled.VarPrefix = defaults.VarPrefix
+	}
+	return &filled
+}
+
+// writeFileAtomic writes content to a temporary file next to outputPath and renames it into place.
+// If outputPath already holds exactly content it is left untouched and false is returned.
+func writeFileAtomic(outputPath string, content []byte) (bool, error) {
+	if existing, err := os.ReadFile(outputPath); err == nil && bytes.Equal(existing, content) {
+		return false, nil
 	}
 
 	// Create temporary file for atomic write
 	tempFile := outputPath + ".tmp"
 	f, err := os.Create(tempFile)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return false, fmt.Errorf("failed to create temp file: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
-	// Execute template
-	if err := tmpl.Execute(f, data); err != nil {
+	if _, err := f.Write(content); err != nil {
 		_ = os.Remove(tempFile) // Best-effort cleanup
-		return fmt.Errorf("failed to execute template: %w", err)
+		return false, fmt.Errorf("failed to write temp file: %w", err)
 	}
 
 	if err := f.Close(); err != nil {
 		_ = os.Remove(tempFile) // Best-effort cleanup
-		return fmt.Errorf("failed to close temp file: %w", err)
+		return false, fmt.Errorf("failed to close temp file: %w", err)
 	}
 
 	// Atomic rename
 	if err := os.Rename(tempFile, outputPath); err != nil {
 		_ = os.Remove(tempFile) // Best-effort cleanup
-		return fmt.Errorf("failed to rename temp file: %w", err)
+		return false, fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
-	return nil
+	return true, nil
 }
 
 // PrepareTemplateData converts Version structs to TemplateData.
 func PrepareTemplateData(versions []Version) *TemplateData {
 	if len(versions) == 0 {
 		return &TemplateData{
-			GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+			GeneratedAt:    generatedAt(),
 			DefaultVersion: "",
 			Versions:       []VersionData{},
 		}
@@ -92,15 +164,38 @@ func PrepareTemplateData(versions []Version) *TemplateData {
 		vd := VersionData{
 			Tag:           v.Tag,
 			ChecksumsByOS: organizePlatformsByOS(v.Checksums),
+			GoVersion:     v.GoVersion,
+			License:       v.License,
+			Provenance:    v.Provenance,
+			OlderMajor:    v.OlderMajor,
 		}
 		versionData = append(versionData, vd)
 	}
 
-	return &TemplateData{
-		GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
-		DefaultVersion: versions[0].Tag, // First version is latest
-		Versions:       versionData,
+	data := &TemplateData{
+		GeneratedAt: generatedAt(),
+		Versions:    versionData,
+		Aliases:     ComputeAliases(versionData),
+		Platforms:   ComputePlatforms(versionData),
+	}
+	// latest-stable cannot fail; callers apply other policies with ApplyDefaultVersion
+	_ = data.ApplyDefaultVersion(DefaultVersionLatestStable)
+	return data
+}
+
+// generatedAt returns the generation timestamp. SOURCE_DATE_EPOCH, if set, replaces the
+// current time so repeated runs over the same releases produce identical output.
+func generatedAt() string {
+	now := time.Now()
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			log.Printf("Warning: ignoring invalid SOURCE_DATE_EPOCH %q", epoch)
+		} else {
+			now = time.Unix(seconds, 0)
+		}
 	}
+	return now.UTC().Format(time.RFC3339)
 }
 
 // SortedArchKeys returns sorted architecture keys for deterministic output.
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// osConstraints maps release OS names to @platforms//os constraint values.
+var osConstraints = map[string]string{
+	"darwin":    "macos",
+	"dragonfly": "dragonfly",
+	"freebsd":   "freebsd",
+	"illumos":   "illumos",
+	"linux":     "linux",
+	"netbsd":    "netbsd",
+	"openbsd":   "openbsd",
+	"solaris":   "solaris",
+	"windows":   "windows",
+}
+
+// cpuConstraints maps release architecture names to @platforms//cpu constraint values.
+var cpuConstraints = map[string]string{
+	"386":     "x86_32",
+	"amd64":   "x86_64",
+	"arm":     "arm",
+	"arm64":   "aarch64",
+	"armv6":   "arm",
+	"armv7":   "armv7",
+	"loong64": "loongarch64",
+	"mips64":  "mips64",
+	"ppc64":   "ppc64",
+	"ppc64le": "ppc64le",
+	"riscv64": "riscv64",
+	"s390x":   "s390x",
+}
+
+// templateFuncs returns the functions available to built-in and user-supplied templates.
+// README.md documents them; keep the two in sync.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"SortedOSKeys":   SortedOSKeys,
+		"SortedArchKeys": SortedArchKeys,
+		"lower":          strings.ToLower,
+		"upper":          strings.ToUpper,
+		"semverCompare":  CompareVersions,
+		"sri":            SRI,
+		"osConstraint":   OSConstraint,
+		"cpuConstraint":  CPUConstraint,
+		"constraints":    Constraints,
+		"goarm":          GOARM,
+		"renderURL":      RenderURL,
+		"quote":          StarlarkQuote,
+	}
+}
+
+// CompareVersions compares two semantic versions, with or without a leading "v".
+// It returns -1, 0 or 1. Prereleases sort before their release, and build metadata is ignored.
+func CompareVersions(a, b string) int {
+	aCore, aPre := splitVersion(a)
+	bCore, bPre := splitVersion(b)
+
+	if c := compareIdentifiers(aCore, bCore, true); c != 0 {
+		return c
+	}
+
+	switch {
+	case aPre == nil && bPre == nil:
+		return 0
+	case aPre == nil:
+		return 1
+	case bPre == nil:
+		return -1
+	}
+	return compareIdentifiers(aPre, bPre, false)
+}
+
+// splitVersion splits a version into its dot-separated core and prerelease identifiers.
+// The prerelease is nil when the version has none.
+func splitVersion(v string) (core, pre []string) {
+	v = strings.TrimPrefix(v, "v")
+	v, _, _ = strings.Cut(v, "+")
+	v, p, hasPre := strings.Cut(v, "-")
+	if hasPre {
+		pre = strings.Split(p, ".")
+	}
+	return strings.Split(v, "."), pre
+}
+
+// compareIdentifiers compares identifiers pairwise: numerically when both are numbers,
+// otherwise lexically with numbers sorting first. Missing core components count as 0;
+// a shorter prerelease sorts first.
+func compareIdentifiers(a, b []string, padZero bool) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		if !padZero && (i >= len(a) || i >= len(b)) {
+			if i >= len(a) {
+				return -1
+			}
+			return 1
+		}
+		x, y := "0", "0"
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		xn, xErr := strconv.ParseUint(x, 10, 64)
+		yn, yErr := strconv.ParseUint(y, 10, 64)
+		switch {
+		case xErr == nil && yErr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case xErr == nil:
+			return -1
+		case yErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+// SRI converts a hex-encoded SHA-256 digest into a Subresource Integrity string
+// ("sha256-<base64>") as accepted by the integrity attribute of Bazel downloads.
+func SRI(sha256Hex string) (string, error) {
+	sum, err := hex.DecodeString(sha256Hex)
+	if err != nil || len(sum) != 32 {
+		return "", fmt.Errorf("invalid SHA-256 digest %q", sha256Hex)
+	}
+	return "sha256-" + base64.StdEncoding.EncodeToString(sum), nil
+}
+
+// OSConstraint returns the @platforms//os label for a release OS name.
+func OSConstraint(os string) (string, error) {
+	value, ok := osConstraints[strings.ToLower(os)]
+	if !ok {
+		return "", fmt.Errorf("no @platforms//os constraint for %q", os)
+	}
+	return "@platforms//os:" + value, nil
+}
+
+// CPUConstraint returns the @platforms//cpu label for a release architecture name.
+// ARM builds for a GOARM level without a dedicated constraint (armv5, armv6) map to arm.
+func CPUConstraint(arch string) (string, error) {
+	value, ok := cpuConstraints[strings.ToLower(arch)]
+	if !ok && GOARM(strings.ToLower(arch)) != "" {
+		value, ok = "arm", true
+	}
+	if !ok {
+		return "", fmt.Errorf("no @platforms//cpu constraint for %q", arch)
+	}
+	return "@platforms//cpu:" + value, nil
+}
+
+// Constraints returns the os and cpu constraint labels for a platform.
+func Constraints(os, arch string) ([]string, error) {
+	osLabel, err := OSConstraint(os)
+	if err != nil {
+		return nil, err
+	}
+	cpuLabel, err := CPUConstraint(arch)
+	if err != nil {
+		return nil, err
+	}
+	return []string{osLabel, cpuLabel}, nil
+}
+
+// RenderURL expands {tag}, {version}, {os}, {arch} and {ext} in urlTemplate for one
+// release archive. {version} is the tag without a leading "v"; {ext} is zip on
+// windows and tar.gz elsewhere.
+func RenderURL(urlTemplate, tag, os, arch string) string {
+	return expandPlaceholders(urlTemplate, map[string]string{
+		"tag":     tag,
+		"version": strings.TrimPrefix(tag, "v"),
+		"os":      os,
+		"arch":    arch,
+		"ext":     archiveExt(os),
+	})
+}
+
+// StarlarkQuote returns s as a double-quoted Starlark string literal.
+func StarlarkQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if c < 0x20 || c == 0x7f {
+				fmt.Fprintf(&b, `\%03o`, c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
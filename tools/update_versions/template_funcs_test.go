@@ -0,0 +1,103 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v2.6.1", "v2.6.1", 0},
+		{"v2.6.1", "2.6.1", 0},
+		{"v2.6.0", "v2.6.1", -1},
+		{"v2.10.0", "v2.9.0", 1},
+		{"v2", "v2.0.0", 0},
+		{"v2.7.0-rc.1", "v2.7.0", -1},
+		{"v2.7.0-rc.2", "v2.7.0-rc.10", -1},
+		{"v2.7.0-alpha", "v2.7.0-beta", -1},
+		{"v2.7.0-rc.1", "v2.7.0-rc", 1},
+		{"v2.7.0-1", "v2.7.0-alpha", -1},
+		{"v2.7.0+build.1", "v2.7.0+build.2", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b), "CompareVersions() should follow semver precedence")
+			assert.Equal(t, -tt.want, CompareVersions(tt.b, tt.a), "CompareVersions() should be antisymmetric")
+		})
+	}
+}
+
+func TestSRI(t *testing.T) {
+	sri, err := SRI("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	require.NoError(t, err, "SRI() should succeed")
+	assert.Equal(t, "sha256-47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=", sri, "SRI() should base64-encode the digest")
+
+	_, err = SRI("abc123")
+	assert.Error(t, err, "SRI() should reject short digests")
+
+	_, err = SRI("zz")
+	assert.Error(t, err, "SRI() should reject non-hex input")
+}
+
+func TestConstraints(t *testing.T) {
+	labels, err := Constraints("darwin", "arm64")
+	require.NoError(t, err, "Constraints() should succeed")
+	assert.Equal(t, []string{"@platforms//os:macos", "@platforms//cpu:aarch64"}, labels, "Constraints() should map Go names to @platforms")
+
+	label, err := CPUConstraint("amd64")
+	require.NoError(t, err, "CPUConstraint() should succeed")
+	assert.Equal(t, "@platforms//cpu:x86_64", label, "CPUConstraint() should map amd64")
+
+	_, err = OSConstraint("plan9")
+	assert.Error(t, err, "OSConstraint() should reject unknown OS")
+
+	_, err = Constraints("linux", "sparc")
+	assert.Error(t, err, "Constraints() should reject unknown architecture")
+}
+
+func TestRenderURL(t *testing.T) {
+	urlTemplate := "https://example.com/{tag}/tool-{version}-{os}-{arch}.{ext}"
+
+	assert.Equal(t, "https://example.com/v1.2.0/tool-1.2.0-linux-amd64.tar.gz",
+		RenderURL(urlTemplate, "v1.2.0", "linux", "amd64"), "RenderURL() should expand placeholders")
+	assert.Equal(t, "https://example.com/v1.2.0/tool-1.2.0-windows-amd64.zip",
+		RenderURL(urlTemplate, "v1.2.0", "windows", "amd64"), "RenderURL() should use zip on windows")
+}
+
+func TestStarlarkQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"v2.6.1", `"v2.6.1"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`C:\tools`, `"C:\\tools"`},
+		{"a\nb\tc", `"a\nb\tc"`},
+		{"bell\x07", `"bell\007"`},
+		{"ünïcode", `"ünïcode"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, StarlarkQuote(tt.in), "StarlarkQuote() should escape Starlark string literal")
+		})
+	}
+}
+
+func TestAssetURLTemplate(t *testing.T) {
+	assert.Equal(t,
+		"https://github.com/golangci/golangci-lint/releases/download/{tag}/golangci-lint-{version}-{os}-{arch}.{ext}",
+		assetURLTemplate(DefaultTool(), NewGitHubClient(nil)),
+		"assetURLTemplate() should keep placeholders for RenderURL")
+
+	assert.Equal(t,
+		"https://mirror.example.com/golangci-lint/{tag}/golangci-lint-{version}-{os}-{arch}.{ext}",
+		assetURLTemplate(DefaultTool(), NewHTTPIndexSource(nil, "https://mirror.example.com/golangci-lint/index.json")),
+		"assetURLTemplate() should restore escaped placeholders")
+}
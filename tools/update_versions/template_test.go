@@ -3,6 +3,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,6 +84,22 @@ func TestPrepareTemplateData(t *testing.T) {
 		// Check it's within the last minute
 		assert.WithinDuration(t, time.Now(), timestamp, time.Minute, "PrepareTemplateData() GeneratedAt timestamp should be recent")
 	})
+
+	t.Run("honours SOURCE_DATE_EPOCH", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "1735689600")
+
+		data := PrepareTemplateData([]Version{{Tag: "v2.6.1", Checksums: map[Platform]string{}}})
+		assert.Equal(t, "2025-01-01T00:00:00Z", data.GeneratedAt, "PrepareTemplateData() should use SOURCE_DATE_EPOCH")
+	})
+
+	t.Run("ignores invalid SOURCE_DATE_EPOCH", func(t *testing.T) {
+		t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+
+		data := PrepareTemplateData([]Version{{Tag: "v2.6.1", Checksums: map[Platform]string{}}})
+		timestamp, err := time.Parse(time.RFC3339, data.GeneratedAt)
+		require.NoError(t, err, "PrepareTemplateData() GeneratedAt timestamp should be valid RFC3339")
+		assert.WithinDuration(t, time.Now(), timestamp, time.Minute, "PrepareTemplateData() should fall back to the current time")
+	})
 }
 
 func TestOrganizePlatformsByOS(t *testing.T) {
@@ -228,7 +245,7 @@ func TestGenerateStarlarkFile(t *testing.T) {
 			},
 		}
 
-		err := GenerateStarlarkFile(data, outputFile)
+		_, err := GenerateStarlarkFile(data, outputFile)
 		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
 
 		// Verify file was created
@@ -269,7 +286,7 @@ func TestGenerateStarlarkFile(t *testing.T) {
 			Versions:       []VersionData{},
 		}
 
-		err := GenerateStarlarkFile(data, outputFile)
+		_, err := GenerateStarlarkFile(data, outputFile)
 		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
 
 		// Check temp file was removed
@@ -278,6 +295,44 @@ func TestGenerateStarlarkFile(t *testing.T) {
 		assert.ErrorIs(t, err, os.ErrNotExist, "GenerateStarlarkFile() should remove temp file")
 	})
 
+	t.Run("leaves identical file untouched", func(t *testing.T) {
+		outputFile := filepath.Join(t.TempDir(), "versions.bzl")
+		data := &TemplateData{
+			GeneratedAt:    "2025-11-11T00:00:00Z",
+			DefaultVersion: "v2.6.1",
+			Versions:       []VersionData{},
+		}
+
+		changed, err := GenerateStarlarkFile(data, outputFile)
+		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+		assert.True(t, changed, "GenerateStarlarkFile() should report a new file as changed")
+
+		past := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(outputFile, past, past), "Failed to set file times")
+
+		changed, err = GenerateStarlarkFile(data, outputFile)
+		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+		assert.False(t, changed, "GenerateStarlarkFile() should report identical content as unchanged")
+
+		info, err := os.Stat(outputFile)
+		require.NoError(t, err, "Failed to stat output file")
+		assert.True(t, info.ModTime().Equal(past), "GenerateStarlarkFile() should not rewrite an unchanged file")
+
+		data.GeneratedAt = "2025-11-12T00:00:00Z"
+		changed, err = GenerateStarlarkFile(data, outputFile)
+		require.NoError(t, err, "GenerateStarlarkFile() should succeed")
+		assert.True(t, changed, "GenerateStarlarkFile() should rewrite a file whose content differs")
+	})
+
+	t.Run("omits empty timestamp", func(t *testing.T) {
+		content, err := RenderStarlark(&TemplateData{DefaultVersion: "v2.6.1", Versions: []VersionData{}})
+		require.NoError(t, err, "RenderStarlark() should succeed")
+
+		assert.NotContains(t, string(content), "Generated at", "RenderStarlark() should omit the timestamp line when GeneratedAt is empty")
+		assert.True(t, strings.HasPrefix(string(content), "# Code generated by //tools/update_versions. DO NOT EDIT.\n\n"),
+			"RenderStarlark() should keep the header")
+	})
+
 	t.Run("handles invalid output path", func(t *testing.T) {
 		data := &TemplateData{
 			GeneratedAt:    "2025-11-11T00:00:00Z",
@@ -286,7 +341,7 @@ func TestGenerateStarlarkFile(t *testing.T) {
 		}
 
 		// Try to write to an invalid path
-		err := GenerateStarlarkFile(data, "/nonexistent/directory/output.bzl")
+		_, err := GenerateStarlarkFile(data, "/nonexistent/directory/output.bzl")
 		assert.Error(t, err, "GenerateStarlarkFile() should error with invalid output path")
 	})
 }
@@ -320,7 +375,7 @@ func TestGenerateStarlarkFile_MultipleVersions(t *testing.T) {
 		},
 	}
 
-	err := GenerateStarlarkFile(data, outputFile)
+	_, err := GenerateStarlarkFile(data, outputFile)
 	require.NoError(t, err, "GenerateStarlarkFile() should succeed")
 
 	content, err := os.ReadFile(outputFile)
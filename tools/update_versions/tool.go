@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DefaultChecksumFile is the checksum file name template used by golangci-lint releases.
+const DefaultChecksumFile = "{name}-{version}-checksums.txt"
+
+// varPrefixRegexp matches a valid Starlark identifier prefix in upper case.
+var varPrefixRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// repoRegexp matches an "owner/name" repository reference; GitLab allows nested groups.
+var repoRegexp = regexp.MustCompile(`^[\w.-]+(/[\w.-]+)+$`)
+
+// Tool describes a tool whose release versions and checksums are maintained.
+type Tool struct {
+	// Name is the tool name, substituted for {name} in templates.
+	Name string `json:"name"`
+	// Repo is the source repository in "owner/name" form. Not used by http-index sources.
+	Repo string `json:"repo,omitempty"`
+	// AssetPattern is the release asset name template (see CompileAssetPattern).
+	AssetPattern string `json:"asset_pattern,omitempty"`
+	// ChecksumFile is the checksum asset name template; supports {name}, {version} and {tag}.
+	// It is used to build the download URL when a source does not report release assets.
+	ChecksumFile string `json:"checksum_file,omitempty"`
+	// ChecksumGlob locates the checksum file among reported release assets (path.Match
+	// syntax, placeholders as for ChecksumFile). Defaults to ChecksumFile.
+	ChecksumGlob string `json:"checksum_glob,omitempty"`
+	// OutputFile is the generated Starlark file path, relative to the workspace root.
+	OutputFile string `json:"output,omitempty"`
+	// Outputs lists additional generated files and their formats.
+	Outputs []Output `json:"outputs,omitempty"`
+	// VarPrefix prefixes the generated Starlark symbols, e.g. GOLANGCI -> GOLANGCI_VERSIONS.
+	VarPrefix string `json:"var_prefix,omitempty"`
+	// CacheSubdir is the subdirectory of the cache directory holding this tool's files.
+	CacheSubdir string `json:"cache_subdir,omitempty"`
+	// DefaultVersion is the default version policy (latest-stable, previous-minor,
+	// oldest-supported) or a tag or alias. Empty means latest-stable.
+	DefaultVersion string `json:"default_version,omitempty"`
+	// Source selects where releases come from. Nil means the runner's default client.
+	Source *SourceConfig `json:"source,omitempty"`
+
+	pattern *AssetPattern
+}
+
+// ToolsConfig is the on-disk format of the --tools-config file.
+type ToolsConfig struct {
+	Tools []Tool `json:"tools"`
+}
+
+// DefaultTool returns the descriptor for golangci-lint, the tool this updater was built for.
+// Its cache lives directly in the cache directory for compatibility with existing caches.
+func DefaultTool() Tool {
+	return Tool{
+		Name:         DefaultToolName,
+		Repo:         "golangci/golangci-lint",
+		AssetPattern: DefaultAssetPattern,
+		ChecksumFile: DefaultChecksumFile,
+		ChecksumGlob: DefaultChecksumFile,
+		OutputFile:   "golangci_lint/private/versions.bzl",
+		VarPrefix:    "GOLANGCI",
+		CacheSubdir:  ".",
+		pattern:      defaultAssetPattern,
+	}
+}
+
+// LoadToolsConfig reads and validates a tools configuration file.
+func LoadToolsConfig(path string) ([]Tool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tools config: %w", err)
+	}
+
+	var cfg ToolsConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse tools config: %w", err)
+	}
+
+	if len(cfg.Tools) == 0 {
+		return nil, fmt.Errorf("tools config %s lists no tools", path)
+	}
+
+	seen := make(map[string]bool)
+	for i := range cfg.Tools {
+		if err := cfg.Tools[i].Validate(); err != nil {
+			return nil, err
+		}
+		if seen[cfg.Tools[i].Name] {
+			return nil, fmt.Errorf("tool %q listed more than once", cfg.Tools[i].Name)
+		}
+		seen[cfg.Tools[i].Name] = true
+	}
+
+	return cfg.Tools, nil
+}
+
+// Validate checks required fields, fills in defaults and compiles the asset pattern.
+func (t *Tool) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if t.Source != nil {
+		if err := t.Source.Validate(); err != nil {
+			return fmt.Errorf("tool %q: %w", t.Name, err)
+		}
+	}
+	if (t.Source == nil || t.Source.Type != SourceHTTPIndex) && !repoRegexp.MatchString(t.Repo) {
+		return fmt.Errorf("tool %q: repo must be in owner/name form, got %q", t.Name, t.Repo)
+	}
+	if t.OutputFile == "" && len(t.Outputs) == 0 {
+		return fmt.Errorf("tool %q: output or outputs is required", t.Name)
+	}
+	if err := validateOutputs(t.Outputs); err != nil {
+		return fmt.Errorf("tool %q: %w", t.Name, err)
+	}
+
+	if t.AssetPattern == "" {
+		t.AssetPattern = DefaultAssetPattern
+	}
+	if t.ChecksumFile == "" {
+		t.ChecksumFile = DefaultChecksumFile
+	}
+	if t.ChecksumGlob == "" {
+		t.ChecksumGlob = t.ChecksumFile
+	}
+	if _, err := path.Match(t.ChecksumGlob, ""); err != nil {
+		return fmt.Errorf("tool %q: invalid checksum_glob %q: %w", t.Name, t.ChecksumGlob, err)
+	}
+	if t.CacheSubdir == "" {
+		t.CacheSubdir = t.Name
+	}
+	if !filepath.IsLocal(t.CacheSubdir) {
+		return fmt.Errorf("tool %q: cache_subdir %q must be a relative path inside the cache directory", t.Name, t.CacheSubdir)
+	}
+	if t.VarPrefix == "" {
+		t.VarPrefix = defaultVarPrefix(t.Name)
+	}
+	if !varPrefixRegexp.MatchString(t.VarPrefix) {
+		return fmt.Errorf("tool %q: var_prefix %q is not a valid upper-case Starlark identifier", t.Name, t.VarPrefix)
+	}
+
+	pattern, err := CompileAssetPattern(t.Name, t.AssetPattern)
+	if err != nil {
+		return fmt.Errorf("tool %q: %w", t.Name, err)
+	}
+	t.pattern = pattern
+
+	return nil
+}
+
+// Pattern returns the compiled asset pattern. It panics for descriptors that have not
+// been validated, whose AssetPattern would otherwise be silently ignored.
+func (t *Tool) Pattern() *AssetPattern {
+	if t.pattern == nil {
+		panic(fmt.Sprintf("tool %q: Pattern called before Validate", t.Name))
+	}
+	return t.pattern
+}
+
+// AllOutputs returns the tool's outputs, with OutputFile as a Starlark output first.
+func (t *Tool) AllOutputs() []Output {
+	var outputs []Output
+	if t.OutputFile != "" {
+		outputs = append(outputs, Output{Path: t.OutputFile, Format: FormatStarlark})
+	}
+	return append(outputs, t.Outputs...)
+}
+
+// ChecksumFileName returns the name of the checksum asset for a release tag.
+func (t *Tool) ChecksumFileName(tag string) string {
+	return expandPlaceholders(t.ChecksumFile, t.releaseVars(tag))
+}
+
+// ChecksumGlobFor returns the glob locating the checksum asset of a release tag.
+func (t *Tool) ChecksumGlobFor(tag string) string {
+	glob := t.ChecksumGlob
+	if glob == "" {
+		glob = t.ChecksumFile
+	}
+	return expandPlaceholders(glob, t.releaseVars(tag))
+}
+
+// releaseVars returns the placeholder values for a release tag.
+func (t *Tool) releaseVars(tag string) map[string]string {
+	return map[string]string{
+		"name":    t.Name,
+		"tag":     tag,
+		"version": strings.TrimPrefix(tag, "v"),
+	}
+}
+
+// defaultVarPrefix derives a Starlark symbol prefix from a tool name, e.g. gofumpt -> GOFUMPT.
+func defaultVarPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTool_Validate(t *testing.T) {
+	t.Run("fills in defaults", func(t *testing.T) {
+		tool := Tool{
+			Name:       "gofumpt",
+			Repo:       "mvdan/gofumpt",
+			OutputFile: "tools/gofumpt/versions.bzl",
+		}
+
+		require.NoError(t, tool.Validate(), "Validate() should succeed")
+		assert.Equal(t, DefaultAssetPattern, tool.AssetPattern, "Validate() should default AssetPattern")
+		assert.Equal(t, DefaultChecksumFile, tool.ChecksumFile, "Validate() should default ChecksumFile")
+		assert.Equal(t, "gofumpt", tool.CacheSubdir, "Validate() should default CacheSubdir to tool name")
+		assert.Equal(t, "GOFUMPT", tool.VarPrefix, "Validate() should derive VarPrefix from tool name")
+	})
+
+	t.Run("http-index source does not need repo", func(t *testing.T) {
+		tool := Tool{
+			Name:       "buf",
+			OutputFile: "out.bzl",
+			Source:     &SourceConfig{Type: SourceHTTPIndex, URL: "https://mirror.example.com/buf/index.json"},
+		}
+
+		assert.NoError(t, tool.Validate(), "Validate() should accept http-index tool without repo")
+	})
+
+	t.Run("accepts nested gitlab project path", func(t *testing.T) {
+		tool := Tool{
+			Name:       "tool",
+			Repo:       "group/subgroup/tool",
+			OutputFile: "out.bzl",
+			Source:     &SourceConfig{Type: SourceGitLab, URL: "https://gitlab.example.com"},
+		}
+
+		assert.NoError(t, tool.Validate(), "Validate() should accept nested project path")
+	})
+
+	t.Run("derives var prefix from hyphenated name", func(t *testing.T) {
+		tool := Tool{Name: "golangci-lint", Repo: "golangci/golangci-lint", OutputFile: "out.bzl"}
+
+		require.NoError(t, tool.Validate(), "Validate() should succeed")
+		assert.Equal(t, "GOLANGCI_LINT", tool.VarPrefix, "Validate() should replace non-alphanumerics with underscores")
+	})
+
+	t.Run("rejects invalid descriptors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			tool Tool
+		}{
+			{"missing name", Tool{Repo: "a/b", OutputFile: "out.bzl"}},
+			{"repo without owner", Tool{Name: "buf", Repo: "buf", OutputFile: "out.bzl"}},
+			{"missing output", Tool{Name: "buf", Repo: "bufbuild/buf"}},
+			{"invalid var prefix", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", VarPrefix: "buf-tool"}},
+			{"invalid asset pattern", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", AssetPattern: "{name}.{ext}"}},
+			{"unknown source type", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", Source: &SourceConfig{Type: "svn"}}},
+			{"gitlab source without url", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", Source: &SourceConfig{Type: SourceGitLab}}},
+			{"cache subdir outside the cache", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", CacheSubdir: "../.."}},
+			{"absolute cache subdir", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", CacheSubdir: "/tmp/buf"}},
+			{"cache subdir escaping through a subdirectory", Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", CacheSubdir: "buf/../../x"}},
+			{"name escaping the cache as default subdir", Tool{Name: "../buf", Repo: "bufbuild/buf", OutputFile: "out.bzl"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Error(t, tt.tool.Validate(), "Validate() should return error")
+			})
+		}
+	})
+}
+
+func TestTool_Pattern_RequiresValidate(t *testing.T) {
+	tool := Tool{Name: "buf", Repo: "bufbuild/buf", OutputFile: "out.bzl", AssetPattern: "{name}-{os}-{arch}.{ext}"}
+	assert.Panics(t, func() { tool.Pattern() }, "Pattern() should not silently ignore an uncompiled AssetPattern")
+
+	require.NoError(t, tool.Validate(), "Validate() should succeed")
+	assert.NotPanics(t, func() { tool.Pattern() }, "Pattern() should return the compiled pattern after Validate()")
+}
+
+func TestTool_ChecksumFileName(t *testing.T) {
+	t.Run("default tool", func(t *testing.T) {
+		tool := DefaultTool()
+
+		assert.Equal(t, "golangci-lint-2.6.1-checksums.txt", tool.ChecksumFileName("v2.6.1"),
+			"ChecksumFileName() should match golangci-lint release layout")
+	})
+
+	t.Run("custom checksum file", func(t *testing.T) {
+		tool := Tool{Name: "buf", Repo: "bufbuild/buf", ChecksumFile: "sha256.txt"}
+
+		assert.Equal(t, "sha256.txt", tool.ChecksumFileName("v1.47.2"),
+			"ChecksumFileName() should use configured checksum file")
+	})
+
+	t.Run("tag placeholder", func(t *testing.T) {
+		tool := Tool{Name: "gofumpt", Repo: "mvdan/gofumpt", ChecksumFile: "{name}_{tag}_sha256sums.txt"}
+
+		assert.Equal(t, "gofumpt_v0.7.0_sha256sums.txt", tool.ChecksumFileName("v0.7.0"),
+			"ChecksumFileName() should substitute the unmodified tag")
+	})
+}
+
+func TestLoadToolsConfig(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tools.json")
+		err := os.WriteFile(path, []byte(`{
+  "tools": [
+    {"name": "golangci-lint", "repo": "golangci/golangci-lint", "output": "golangci_lint/private/versions.bzl", "var_prefix": "GOLANGCI", "cache_subdir": "."},
+    {"name": "buf", "repo": "bufbuild/buf", "asset_pattern": "{name}-{os}-{arch}.{ext}", "checksum_file": "sha256.txt", "output": "buf/private/versions.bzl"}
+  ]
+}`), 0644)
+		require.NoError(t, err, "Failed to write config")
+
+		tools, err := LoadToolsConfig(path)
+		require.NoError(t, err, "LoadToolsConfig() should succeed")
+		require.Len(t, tools, 2, "LoadToolsConfig() should return all tools")
+
+		assert.Equal(t, "GOLANGCI", tools[0].VarPrefix, "LoadToolsConfig() should preserve explicit var_prefix")
+		assert.Equal(t, ".", tools[0].CacheSubdir, "LoadToolsConfig() should preserve explicit cache_subdir")
+		assert.Equal(t, "BUF", tools[1].VarPrefix, "LoadToolsConfig() should derive var_prefix")
+
+		platform, err := tools[1].Pattern().ExtractPlatform("buf-Linux-x86_64.tar.gz")
+		require.NoError(t, err, "LoadToolsConfig() should compile asset patterns")
+		assert.Equal(t, Platform{OS: "Linux", Arch: "x86_64"}, *platform, "compiled pattern should extract platform")
+	})
+
+	t.Run("duplicate tool", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tools.json")
+		err := os.WriteFile(path, []byte(`{"tools": [
+    {"name": "buf", "repo": "bufbuild/buf", "output": "a.bzl"},
+    {"name": "buf", "repo": "bufbuild/buf", "output": "b.bzl"}
+]}`), 0644)
+		require.NoError(t, err, "Failed to write config")
+
+		_, err = LoadToolsConfig(path)
+		assert.Error(t, err, "LoadToolsConfig() should reject duplicate tools")
+	})
+
+	t.Run("empty config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "tools.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"tools": []}`), 0644), "Failed to write config")
+
+		_, err := LoadToolsConfig(path)
+		assert.Error(t, err, "LoadToolsConfig() should reject config without tools")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadToolsConfig(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err, "LoadToolsConfig() should error on missing file")
+	})
+}
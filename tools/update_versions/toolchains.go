@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// repoNameRegexp matches characters not allowed in a Bazel repository name.
+var repoNameRegexp = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// ToolchainRepo is the archive repository of one version on one platform.
+type ToolchainRepo struct {
+	// Name is the repository name, e.g. golangci_lint_v2_6_1_linux_amd64.
+	Name        string
+	Version     string
+	Platform    string // "<os>_<arch>"
+	URL         string
+	SHA256      string
+	StripPrefix string
+	// Binary is the executable inside the archive, with .exe on windows.
+	Binary      string
+	Constraints []string
+}
+
+// toolchainsData holds the data for rendering toolchains.bzl.tmpl.
+type toolchainsData struct {
+	*TemplateData
+	Repos []ToolchainRepo
+}
+
+// ComputeToolchainRepos returns a repository for every version and mapped platform, in
+// version order and then by platform. It needs data.URLTemplate to locate the archives.
+func ComputeToolchainRepos(data *TemplateData) ([]ToolchainRepo, error) {
+	if data.URLTemplate == "" {
+		return nil, fmt.Errorf("toolchain repositories need the archive URL of the release source")
+	}
+
+	prefix := repoNameRegexp.ReplaceAllString(data.ToolName, "_")
+	var repos []ToolchainRepo
+	for _, v := range data.Versions {
+		for _, p := range data.Platforms {
+			sha256, ok := v.ChecksumsByOS[p.OS][p.Arch]
+			if !ok {
+				continue
+			}
+
+			url := RenderURL(data.URLTemplate, v.Tag, p.OS, p.Arch)
+			binary := data.ToolName
+			if p.OS == "windows" {
+				binary += ".exe"
+			}
+			repos = append(repos, ToolchainRepo{
+				Name:        prefix + "_" + repoNameRegexp.ReplaceAllString(v.Tag, "_") + "_" + p.Name(),
+				Version:     v.Tag,
+				Platform:    p.Name(),
+				URL:         url,
+				SHA256:      sha256,
+				StripPrefix: archiveRoot(url),
+				Binary:      binary,
+				Constraints: p.Constraints,
+			})
+		}
+	}
+	return repos, nil
+}
+
+// archiveRoot returns the top-level directory of a release archive, which by the
+// GoReleaser convention is the archive name without its extension.
+func archiveRoot(url string) string {
+	name := path.Base(url)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(name, ext) {
+			return strings.TrimSuffix(name, ext)
+		}
+	}
+	return name
+}
+
+// GenerateToolchainsFile writes a .bzl file declaring the repository of every version and
+// platform and the toolchain() targets registering them.
+func GenerateToolchainsFile(data *TemplateData, outputPath string) (bool, error) {
+	data = withToolDefaults(data)
+	repos, err := ComputeToolchainRepos(data)
+	if err != nil {
+		return false, err
+	}
+
+	content, err := renderTemplate("toolchains.bzl.tmpl", &toolchainsData{TemplateData: data, Repos: repos})
+	if err != nil {
+		return false, err
+	}
+	if err := CheckStarlarkSyntax(filepath.Base(outputPath), content); err != nil {
+		return false, err
+	}
+	return writeStarlarkFileAtomic(outputPath, content)
+}
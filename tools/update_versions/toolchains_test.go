@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+)
+
+// toolchainsTestData returns two versions, the older one without a windows build.
+func toolchainsTestData() *TemplateData {
+	data := &TemplateData{
+		ToolName:       "golangci-lint",
+		VarPrefix:      "GOLANGCI",
+		DefaultVersion: "v2.6.1",
+		URLTemplate:    "https://github.com/golangci/golangci-lint/releases/download/{tag}/golangci-lint-{version}-{os}-{arch}.{ext}",
+		Versions: []VersionData{
+			{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"armv7": "aaa"}, "windows": {"amd64": "bbb"}}},
+			{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{"linux": {"armv7": "ccc"}}},
+		},
+	}
+	data.Platforms = ComputePlatforms(data.Versions)
+	return data
+}
+
+func TestComputeToolchainRepos(t *testing.T) {
+	repos, err := ComputeToolchainRepos(toolchainsTestData())
+	require.NoError(t, err, "ComputeToolchainRepos() should succeed")
+
+	assert.Equal(t, []ToolchainRepo{
+		{
+			Name:        "golangci_lint_v2_6_1_linux_armv7",
+			Version:     "v2.6.1",
+			Platform:    "linux_armv7",
+			URL:         "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-linux-armv7.tar.gz",
+			SHA256:      "aaa",
+			StripPrefix: "golangci-lint-2.6.1-linux-armv7",
+			Binary:      "golangci-lint",
+			Constraints: []string{"@platforms//os:linux", "@platforms//cpu:armv7"},
+		},
+		{
+			Name:        "golangci_lint_v2_6_1_windows_amd64",
+			Version:     "v2.6.1",
+			Platform:    "windows_amd64",
+			URL:         "https://github.com/golangci/golangci-lint/releases/download/v2.6.1/golangci-lint-2.6.1-windows-amd64.zip",
+			SHA256:      "bbb",
+			StripPrefix: "golangci-lint-2.6.1-windows-amd64",
+			Binary:      "golangci-lint.exe",
+			Constraints: []string{"@platforms//os:windows", "@platforms//cpu:x86_64"},
+		},
+		{
+			Name:        "golangci_lint_v2_6_0_linux_armv7",
+			Version:     "v2.6.0",
+			Platform:    "linux_armv7",
+			URL:         "https://github.com/golangci/golangci-lint/releases/download/v2.6.0/golangci-lint-2.6.0-linux-armv7.tar.gz",
+			SHA256:      "ccc",
+			StripPrefix: "golangci-lint-2.6.0-linux-armv7",
+			Binary:      "golangci-lint",
+			Constraints: []string{"@platforms//os:linux", "@platforms//cpu:armv7"},
+		},
+	}, repos, "ComputeToolchainRepos() should list every version and platform")
+
+	_, err = ComputeToolchainRepos(&TemplateData{ToolName: "golangci-lint"})
+	assert.Error(t, err, "ComputeToolchainRepos() should require a URL template")
+}
+
+func TestGenerateToolchainsFile(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "toolchains.bzl")
+	_, err := GenerateToolchainsFile(toolchainsTestData(), outputFile)
+	require.NoError(t, err, "GenerateToolchainsFile() should succeed")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output")
+
+	// Execute the file with http_archive recorded instead of declared
+	var archives []starlark.Tuple
+	thread := &starlark.Thread{
+		Name: "test",
+		Load: func(_ *starlark.Thread, module string) (starlark.StringDict, error) {
+			require.Equal(t, "@bazel_tools//tools/build_defs/repo:http.bzl", module, "toolchains.bzl should only load http.bzl")
+			return starlark.StringDict{
+				"http_archive": starlark.NewBuiltin("http_archive", func(_ *starlark.Thread, _ *starlark.Builtin, _ starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+					archives = append(archives, kwargs...)
+					return starlark.None, nil
+				}),
+			}, nil
+		},
+	}
+	globals, err := starlark.ExecFileOptions(bzlFileOptions, thread, "toolchains.bzl", content, starlark.StringDict{
+		"fail": starlark.NewBuiltin("fail", starlarkFail),
+	})
+	require.NoError(t, err, "generated file should execute")
+
+	names, err := starlark.Call(thread, globals["golangci_toolchain_repositories"], starlark.Tuple{starlark.String("//rules:toolchain.bzl")}, nil)
+	require.NoError(t, err, "golangci_toolchain_repositories() should succeed")
+	assert.Equal(t, `["golangci_lint_v2_6_1_linux_armv7", "golangci_lint_v2_6_1_windows_amd64", "golangci_lint_v2_6_0_linux_armv7"]`,
+		names.String(), "golangci_toolchain_repositories() should return the repository names")
+	assert.Contains(t, archives, starlark.Tuple{starlark.String("strip_prefix"), starlark.String("golangci-lint-2.6.1-windows-amd64")},
+		"http_archive() should strip the archive root")
+
+	build, err := starlark.Call(thread, globals["golangci_toolchains_build_file"], starlark.Tuple{starlark.String("//golangci_lint:toolchain_type"), starlark.String("v2.6.1")}, nil)
+	require.NoError(t, err, "golangci_toolchains_build_file() should succeed")
+	buildFile, _ := starlark.AsString(build)
+	assert.NoError(t, CheckStarlarkSyntax("BUILD.bazel", []byte(buildFile)), "hub BUILD file should parse")
+	assert.Contains(t, buildFile, `toolchain(
+    name = "v2.6.0_linux_armv7",
+    exec_compatible_with = ["@platforms//os:linux", "@platforms//cpu:armv7"],
+    target_settings = [":is_v2.6.0"],
+    toolchain = "@golangci_lint_v2_6_0_linux_armv7//:toolchain",
+    toolchain_type = "//golangci_lint:toolchain_type",
+)`, "hub BUILD file should register a toolchain per version and platform")
+	assert.Contains(t, buildFile, `build_setting_default = "v2.6.1",`, "hub BUILD file should default to the given version")
+
+	_, err = starlark.Call(thread, globals["golangci_toolchains_build_file"], starlark.Tuple{starlark.String("//golangci_lint:toolchain_type"), starlark.String("v1.64.8")}, nil)
+	assert.Error(t, err, "golangci_toolchains_build_file() should reject a version without toolchains")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// bzlFileOptions matches the .bzl dialect closely enough for generated files: no
+// top-level control flow, no global reassignment.
+var bzlFileOptions = &syntax.FileOptions{}
+
+// ValidateStarlark executes a rendered versions file and calls get_<prefix>_version_info
+// for the default version, every listed version and every alias, checking each returns
+// (version, dict) with the tag it should resolve to.
+func ValidateStarlark(filename string, content []byte, data *TemplateData) error {
+	thread := &starlark.Thread{Name: "validate " + filename}
+	globals, err := starlark.ExecFileOptions(bzlFileOptions, thread, filename, content, starlark.StringDict{
+		"fail": starlark.NewBuiltin("fail", starlarkFail),
+	})
+	if err != nil {
+		return fmt.Errorf("generated Starlark is invalid: %w", err)
+	}
+
+	funcName := "get_" + strings.ToLower(data.VarPrefix) + "_version_info"
+	fn, ok := globals[funcName].(starlark.Callable)
+	if !ok {
+		return fmt.Errorf("generated Starlark does not define %s", funcName)
+	}
+
+	// The default can only resolve when there is something to resolve it to
+	if len(data.Versions) > 0 {
+		if err := checkVersionInfo(thread, fn, starlark.None, data.DefaultVersion); err != nil {
+			return fmt.Errorf("%s(): %w", funcName, err)
+		}
+	}
+	for _, v := range data.Versions {
+		if err := checkVersionInfo(thread, fn, starlark.String(v.Tag), v.Tag); err != nil {
+			return fmt.Errorf("%s(%q): %w", funcName, v.Tag, err)
+		}
+	}
+	for _, a := range data.Aliases {
+		if err := checkVersionInfo(thread, fn, starlark.String(a.Name), a.Version); err != nil {
+			return fmt.Errorf("%s(%q): %w", funcName, a.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// checkVersionInfo calls a get_*_version_info function and checks its result.
+func checkVersionInfo(thread *starlark.Thread, fn starlark.Callable, arg starlark.Value, want string) error {
+	result, err := starlark.Call(thread, fn, starlark.Tuple{arg}, nil)
+	if err != nil {
+		return err
+	}
+
+	tuple, ok := result.(starlark.Tuple)
+	if !ok || len(tuple) != 2 {
+		return fmt.Errorf("returned %s, want (version, checksums)", result.Type())
+	}
+	if got, ok := starlark.AsString(tuple[0]); !ok || got != want {
+		return fmt.Errorf("returned version %s, want %q", tuple[0], want)
+	}
+	if _, ok := tuple[1].(*starlark.Dict); !ok {
+		return fmt.Errorf("returned checksums of type %s, want dict", tuple[1].Type())
+	}
+
+	return nil
+}
+
+// CheckStarlarkSyntax parses content as a .bzl file. It is used for outputs that cannot be
+// executed standalone, such as loaders that need a module context or custom templates.
+func CheckStarlarkSyntax(filename string, content []byte) error {
+	if _, err := bzlFileOptions.Parse(filename, content, 0); err != nil {
+		return fmt.Errorf("generated Starlark is invalid: %w", err)
+	}
+	return nil
+}
+
+// starlarkFail implements Bazel's fail() builtin.
+func starlarkFail(_ *starlark.Thread, _ *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	parts := make([]string, 0, len(args)+len(kwargs))
+	for _, a := range args {
+		if s, ok := starlark.AsString(a); ok {
+			parts = append(parts, s)
+		} else {
+			parts = append(parts, a.String())
+		}
+	}
+	for _, kv := range kwargs {
+		if s, ok := starlark.AsString(kv[1]); ok {
+			parts = append(parts, s)
+		}
+	}
+	return nil, errors.New(strings.Join(parts, " "))
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStarlark(t *testing.T) {
+	data := &TemplateData{
+		VarPrefix:      "GOLANGCI",
+		DefaultVersion: "v2.6.1",
+		Versions:       []VersionData{{Tag: "v2.6.1"}, {Tag: "v2.6.0"}},
+	}
+
+	t.Run("rendered template", func(t *testing.T) {
+		data := &TemplateData{
+			DefaultVersion: "v2.6.1",
+			Versions: []VersionData{
+				{Tag: "v2.6.1", ChecksumsByOS: map[string]map[string]string{"linux": {"amd64": "abc"}}},
+				{Tag: "v2.6.0", ChecksumsByOS: map[string]map[string]string{"darwin": {"arm64": "def"}}},
+			},
+		}
+		data.Aliases = ComputeAliases(data.Versions)
+		content, err := RenderStarlark(data)
+		require.NoError(t, err, "RenderStarlark() should succeed")
+
+		assert.NoError(t, ValidateStarlark("versions.bzl", content, withToolDefaults(data)), "ValidateStarlark() should accept rendered output")
+	})
+
+	t.Run("unresolved alias", func(t *testing.T) {
+		data := *data
+		data.Aliases = []Alias{{Name: "v2.6", Version: "v2.6.1"}}
+		content := `
+def get_golangci_version_info(version = None):
+    return version or "v2.6.1", {}
+`
+		assert.Error(t, ValidateStarlark("versions.bzl", []byte(content), &data), "ValidateStarlark() should check aliases resolve")
+	})
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"syntax error", `GOLANGCI_VERSIONS = {`},
+		{"missing function", `GOLANGCI_VERSIONS = {}`},
+		{"unknown version", `
+def get_golangci_version_info(version = None):
+    fail("Unknown version:", version)
+`},
+		{"wrong default", `
+def get_golangci_version_info(version = None):
+    return version or "v2.6.0", {}
+`},
+		{"wrong return type", `
+def get_golangci_version_info(version = None):
+    return version
+`},
+		{"checksums not a dict", `
+def get_golangci_version_info(version = None):
+    return version or "v2.6.1", []
+`},
+		{"top-level control flow", `
+for v in ["v2.6.1"]:
+    pass
+
+def get_golangci_version_info(version = None):
+    return version or "v2.6.1", {}
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, ValidateStarlark("versions.bzl", []byte(tt.content), data), "ValidateStarlark() should return error")
+		})
+	}
+}
+
+func TestCheckStarlarkSyntax(t *testing.T) {
+	assert.NoError(t, CheckStarlarkSyntax("repos.bzl", []byte(`load("@bazel_tools//tools/build_defs/repo:http.bzl", "http_archive")`)),
+		"CheckStarlarkSyntax() should accept load statements")
+	assert.Error(t, CheckStarlarkSyntax("repos.bzl", []byte(`x = [`)), "CheckStarlarkSyntax() should reject syntax errors")
+}
+
+func TestGenerateStarlarkFile_InvalidOutputKeepsOldFile(t *testing.T) {
+	outputFile := filepath.Join(t.TempDir(), "versions.bzl")
+	require.NoError(t, os.WriteFile(outputFile, []byte("# previous\n"), 0644), "Failed to write existing file")
+
+	data := &TemplateData{
+		DefaultVersion: `v2.6.1"`,
+		Versions:       []VersionData{{Tag: `v2.6.1"`}},
+	}
+
+	_, err := GenerateStarlarkFile(data, outputFile)
+	assert.Error(t, err, "GenerateStarlarkFile() should reject output that is not valid Starlark")
+
+	content, err := os.ReadFile(outputFile)
+	require.NoError(t, err, "Failed to read output file")
+	assert.Equal(t, "# previous\n", string(content), "GenerateStarlarkFile() should leave the old file in place")
+	assert.NoFileExists(t, outputFile+".tmp", "GenerateStarlarkFile() should not leave a temp file")
+}